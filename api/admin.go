@@ -0,0 +1,289 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/utils"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+type syncRun struct {
+	ID              int32      `json:"id"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	Status          string     `json:"status"`
+	Source          string     `json:"source"`
+	SourceVersion   string     `json:"source_version"`
+	Release         string     `json:"release,omitempty"`
+	ArchiveChecksum string     `json:"archive_checksum,omitempty"`
+	Packs           []string   `json:"packs,omitempty"`
+	FilesSeen       int32      `json:"files_seen"`
+	FilesParsed     int32      `json:"files_parsed"`
+	RecordsInserted int32      `json:"records_inserted"`
+	RecordsUpdated  int32      `json:"records_updated"`
+	FilesSkipped    int32      `json:"files_skipped"`
+	FilesFailed     int32      `json:"files_failed"`
+	Error           string     `json:"error,omitempty"`
+}
+
+type syncFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+type syncRunDetail struct {
+	syncRun
+	Failures []syncFailure `json:"failures"`
+}
+
+type syncRunsResponse struct {
+	Results []syncRun `json:"results"`
+	Limit   int32     `json:"limit"`
+	Offset  int32     `json:"offset"`
+}
+
+type quarantinedItem struct {
+	ID     int32  `json:"id"`
+	RunID  int32  `json:"run_id"`
+	Path   string `json:"path"`
+	Item   string `json:"item,omitempty"`
+	Reason string `json:"reason"`
+}
+
+type quarantinedItemDetail struct {
+	quarantinedItem
+	Raw json.RawMessage `json:"raw"`
+}
+
+type quarantineResponse struct {
+	Results []quarantinedItem `json:"results"`
+	Limit   int32             `json:"limit"`
+	Offset  int32             `json:"offset"`
+}
+
+func newSyncRun(row writeMonsters.SyncRun) syncRun {
+	run := syncRun{
+		ID:              row.ID,
+		StartedAt:       row.StartedAt.Time,
+		Status:          row.Status,
+		Source:          row.Source,
+		SourceVersion:   row.SourceVersion.String,
+		Release:         row.Release.String,
+		ArchiveChecksum: row.ArchiveChecksum.String,
+		Packs:           row.PackFilter,
+		FilesSeen:       row.FilesSeen,
+		FilesParsed:     row.FilesParsed,
+		RecordsInserted: row.RecordsInserted,
+		RecordsUpdated:  row.RecordsUpdated,
+		FilesSkipped:    row.FilesSkipped,
+		FilesFailed:     row.FilesFailed,
+		Error:           row.Error.String,
+	}
+	if row.FinishedAt.Valid {
+		run.FinishedAt = &row.FinishedAt.Time
+	}
+	return run
+}
+
+// parsePage reads the limit and offset query parameters of an admin listing.
+func parsePage(r *http.Request) (limit, offset int32, err error) {
+	limit = defaultSearchLimit
+	param, err := optionalInt(r, "limit")
+	if err != nil {
+		return 0, 0, err
+	}
+	if param.Valid {
+		if param.Int32 < 1 || param.Int32 > maxSearchLimit {
+			return 0, 0, &paramError{Param: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxSearchLimit)}
+		}
+		limit = param.Int32
+	}
+	param, err = optionalInt(r, "offset")
+	if err != nil {
+		return 0, 0, err
+	}
+	if param.Valid {
+		if param.Int32 < 0 {
+			return 0, 0, &paramError{Param: "offset", Message: "must not be negative"}
+		}
+		offset = param.Int32
+	}
+	return limit, offset, nil
+}
+
+type syncTriggered struct {
+	// RunID is the run of the first data source, kept for clients that
+	// predate RunIDs.
+	RunID  int32   `json:"run_id"`
+	RunIDs []int32 `json:"run_ids"`
+	Status string  `json:"status"`
+}
+
+// TriggerSync starts a sync of every data source in the background. It
+// answers 202 with the id of the run of each source, which can be polled
+// under /v1/admin/sync/runs.
+func TriggerSync(syncs SyncTrigger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if syncs == nil {
+			writeError(w, http.StatusServiceUnavailable, "sync_unavailable", "syncs cannot be started on this instance")
+			return
+		}
+		runIDs, err := syncs.Trigger()
+		if errors.Is(err, utils.ErrSyncInProgress) {
+			writeError(w, http.StatusConflict, "sync_in_progress", err.Error())
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to start sync", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to start a sync")
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("/v1/admin/sync/runs/%d", runIDs[0]))
+		writeJSON(w, http.StatusAccepted, syncTriggered{RunID: runIDs[0], RunIDs: runIDs, Status: utils.SyncRunning})
+	}
+}
+
+// ListSyncRuns returns the sync history, newest first.
+func ListSyncRuns(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePage(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		params := writeMonsters.ListSyncRunsParams{PageLimit: limit, PageOffset: offset}
+		rows, err := writeMonsters.New(cfg.DBPool).ListSyncRuns(r.Context(), params)
+		if err != nil {
+			logger.Log.Error("failed to list sync runs", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to list sync runs")
+			return
+		}
+		resp := syncRunsResponse{
+			Results: make([]syncRun, 0, len(rows)),
+			Limit:   params.PageLimit,
+			Offset:  params.PageOffset,
+		}
+		for _, row := range rows {
+			resp.Results = append(resp.Results, newSyncRun(row))
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// GetSyncRun returns one sync run with every file that failed to load.
+func GetSyncRun(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_id", "sync run id must be an integer")
+			return
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		row, err := queries.GetSyncRun(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "sync_run_not_found", "no sync run exists with that id")
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to load sync run", "id", id, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load sync run")
+			return
+		}
+		failures, err := queries.ListSyncFailures(r.Context(), int32(id))
+		if err != nil {
+			logger.Log.Error("failed to load sync failures", "id", id, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load sync run")
+			return
+		}
+		resp := syncRunDetail{syncRun: newSyncRun(row), Failures: make([]syncFailure, 0, len(failures))}
+		for _, failure := range failures {
+			resp.Failures = append(resp.Failures, syncFailure{Path: failure.Path, Error: failure.Error})
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// ListQuarantine lists the documents and embedded items syncs could not
+// classify, newest first. It can be narrowed to one run with run_id and to
+// matching reasons with reason.
+func ListQuarantine(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := parsePage(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		runID, err := optionalInt(r, "run_id")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		rows, err := writeMonsters.New(cfg.DBPool).ListQuarantinedItems(r.Context(), writeMonsters.ListQuarantinedItemsParams{
+			RunID:      runID,
+			Reason:     optionalText(r, "reason"),
+			PageLimit:  limit,
+			PageOffset: offset,
+		})
+		if err != nil {
+			logger.Log.Error("failed to list quarantined items", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to list quarantined items")
+			return
+		}
+		resp := quarantineResponse{Results: make([]quarantinedItem, 0, len(rows)), Limit: limit, Offset: offset}
+		for _, row := range rows {
+			resp.Results = append(resp.Results, quarantinedItem{
+				ID:     row.ID,
+				RunID:  row.RunID,
+				Path:   row.Path,
+				Item:   row.ItemName.String,
+				Reason: row.Reason,
+			})
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// GetQuarantinedItem returns one quarantined entry with its raw JSON. Entries
+// quarantined for not being valid JSON return the raw text as a string.
+func GetQuarantinedItem(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_id", "quarantined item id must be an integer")
+			return
+		}
+		row, err := writeMonsters.New(cfg.DBPool).GetQuarantinedItem(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "quarantined_item_not_found", "no quarantined item exists with that id")
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to load quarantined item", "id", id, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load quarantined item")
+			return
+		}
+		raw := json.RawMessage(row.Raw)
+		if !json.Valid(raw) {
+			raw, _ = json.Marshal(row.Raw)
+		}
+		writeJSON(w, http.StatusOK, quarantinedItemDetail{
+			quarantinedItem: quarantinedItem{
+				ID:     row.ID,
+				RunID:  row.RunID,
+				Path:   row.Path,
+				Item:   row.ItemName.String,
+				Reason: row.Reason,
+			},
+			Raw: raw,
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/utils"
+)
+
+const testAdminToken = "test-token"
+
+func adminRouter(syncs SyncTrigger) http.Handler {
+	return NewRouter(config.Config{ADMIN_TOKEN: testAdminToken}, syncs)
+}
+
+func adminRequest(method, path string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return req
+}
+
+func TestGetSyncRunInvalidID(t *testing.T) {
+	router := adminRouter(nil)
+	req := adminRequest(http.MethodGet, "/v1/admin/sync/runs/latest")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	var body errorBody
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON error body, got %v", err)
+	}
+	if body.Error.Code != "invalid_id" {
+		t.Errorf("Expected code 'invalid_id', got '%s'", body.Error.Code)
+	}
+}
+
+func TestListSyncRunsInvalidLimit(t *testing.T) {
+	router := adminRouter(nil)
+	for _, query := range []string{"limit=0", "limit=1000", "offset=-1", "limit=ten"} {
+		req := adminRequest(http.MethodGet, "/v1/admin/sync/runs?"+query)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, rec.Code)
+			continue
+		}
+		var body errorBody
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: expected JSON error body, got %v", query, err)
+		}
+		if body.Error.Code != "invalid_parameter" {
+			t.Errorf("%s: expected code 'invalid_parameter', got '%s'", query, body.Error.Code)
+		}
+	}
+}
+
+func TestQuarantineInvalidParams(t *testing.T) {
+	router := adminRouter(nil)
+	tests := []struct {
+		path string
+		code string
+	}{
+		{"/v1/admin/quarantine/abc", "invalid_id"},
+		{"/v1/admin/quarantine?run_id=latest", "invalid_parameter"},
+		{"/v1/admin/quarantine?limit=0", "invalid_parameter"},
+	}
+	for _, tt := range tests {
+		req := adminRequest(http.MethodGet, tt.path)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.path, http.StatusBadRequest, rec.Code)
+			continue
+		}
+		var body errorBody
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: expected JSON error body, got %v", tt.path, err)
+		}
+		if body.Error.Code != tt.code {
+			t.Errorf("%s: expected code '%s', got '%s'", tt.path, tt.code, body.Error.Code)
+		}
+	}
+}
+
+type fakeSyncTrigger struct {
+	runIDs []int32
+	err    error
+}
+
+func (f fakeSyncTrigger) Trigger() ([]int32, error) {
+	return f.runIDs, f.err
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+	}{
+		{"no token configured", "", "Bearer "},
+		{"missing header", testAdminToken, ""},
+		{"wrong token", testAdminToken, "Bearer nope"},
+		{"wrong scheme", testAdminToken, "Basic " + testAdminToken},
+	}
+	for _, tt := range tests {
+		router := NewRouter(config.Config{ADMIN_TOKEN: tt.token}, fakeSyncTrigger{runIDs: []int32{1}})
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/sync", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status %d, got %d", tt.name, http.StatusUnauthorized, rec.Code)
+		}
+	}
+}
+
+func TestTriggerSync(t *testing.T) {
+	tests := []struct {
+		name    string
+		syncs   SyncTrigger
+		status  int
+		errCode string
+	}{
+		{"started", fakeSyncTrigger{runIDs: []int32{7, 8}}, http.StatusAccepted, ""},
+		{"already running", fakeSyncTrigger{err: utils.ErrSyncInProgress}, http.StatusConflict, "sync_in_progress"},
+		{"no scheduler", nil, http.StatusServiceUnavailable, "sync_unavailable"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		adminRouter(tt.syncs).ServeHTTP(rec, adminRequest(http.MethodPost, "/v1/admin/sync"))
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, rec.Code)
+			continue
+		}
+		if tt.errCode == "" {
+			var body syncTriggered
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("%s: expected JSON body, got %v", tt.name, err)
+			}
+			if body.RunID != 7 || len(body.RunIDs) != 2 || body.Status != utils.SyncRunning {
+				t.Errorf("%s: unexpected body %+v", tt.name, body)
+			}
+			if loc := rec.Header().Get("Location"); loc != "/v1/admin/sync/runs/7" {
+				t.Errorf("%s: unexpected Location '%s'", tt.name, loc)
+			}
+			continue
+		}
+		var body errorBody
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: expected JSON error body, got %v", tt.name, err)
+		}
+		if body.Error.Code != tt.errCode {
+			t.Errorf("%s: expected code '%s', got '%s'", tt.name, tt.errCode, body.Error.Code)
+		}
+	}
+}
+
+func TestListReleasesWithoutGitHubRepo(t *testing.T) {
+	router := NewRouter(config.Config{ADMIN_TOKEN: testAdminToken, REPO_URL: "https://example.com/pf2e.tar.gz"}, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, adminRequest(http.MethodGet, "/v1/admin/upstream/releases"))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	var body errorBody
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON error body, got %v", err)
+	}
+	if body.Error.Code != "releases_unavailable" {
+		t.Errorf("Expected code 'releases_unavailable', got '%s'", body.Error.Code)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/utils"
+)
+
+const (
+	maxPartySize = 50
+	// GM Core's adjustment is written for parties of three to six. Outside
+	// that range the budget is still extrapolated but we say so.
+	minTablePartySize = 3
+	maxTablePartySize = 6
+)
+
+type budgetResponse struct {
+	PartyLevel int                        `json:"party_level,omitempty"`
+	PartySize  int                        `json:"party_size"`
+	Difficulty string                     `json:"difficulty,omitempty"`
+	Budget     *int                       `json:"budget,omitempty"`
+	Tiers      []structs.TierBudget       `json:"tiers"`
+	Warnings   []structs.EncounterWarning `json:"warnings"`
+}
+
+// budgetParty reads the party from either ?levels=5,5,6,4 or ?psize=4.
+func budgetParty(r *http.Request) (utils.Party, *apiError) {
+	if raw := listParam(r, "levels"); len(raw) > 0 {
+		levels := make([]int, 0, len(raw))
+		for _, v := range raw {
+			level, err := strconv.Atoi(v)
+			if err != nil {
+				return utils.Party{}, &apiError{Code: "invalid_party_level", Message: "levels must be a list of integers"}
+			}
+			levels = append(levels, level)
+		}
+		return partyFromRequest(0, 0, levels)
+	}
+	psizeStr := r.URL.Query().Get("psize")
+	Psize, err := strconv.Atoi(psizeStr)
+	if err != nil {
+		return utils.Party{}, &apiError{Code: "invalid_party_size", Message: "psize must be an integer"}
+	}
+	if Psize < 1 || Psize > maxPartySize {
+		return utils.Party{}, &apiError{Code: "invalid_party_size", Message: fmt.Sprintf("psize must be between 1 and %d", maxPartySize)}
+	}
+	return utils.NewParty(0, Psize), nil
+}
+
+// CalculatexpBudget returns the party's XP budget for every threat tier, and
+// for the requested difficulty when one is given.
+func CalculatexpBudget(w http.ResponseWriter, r *http.Request) {
+	party, perr := budgetParty(r)
+	if perr != nil {
+		writeError(w, http.StatusBadRequest, perr.Code, perr.Message)
+		return
+	}
+	Psize := party.Size
+
+	tiers, err := party.TierBudgets()
+	if err != nil {
+		logger.Log.Error("unable to calculate xp budget", "err", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "unable to calculate xp budget")
+		return
+	}
+	resp := budgetResponse{
+		PartyLevel: party.Level,
+		PartySize:  Psize,
+		Tiers:      tiers,
+		Warnings:   []structs.EncounterWarning{},
+	}
+
+	if difficulty := strings.ToLower(r.URL.Query().Get("difficulty")); difficulty != "" {
+		xpBudget, err := party.Budget(difficulty)
+		if errors.Is(err, utils.ErrInvalidDifficulty) {
+			writeError(w, http.StatusBadRequest, "invalid_difficulty", "difficulty must be one of "+strings.Join(utils.ThreatTiers, ", "))
+			return
+		}
+		if err != nil {
+			logger.Log.Error("unable to calculate xp budget", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to calculate xp budget")
+			return
+		}
+		resp.Difficulty = difficulty
+		resp.Budget = &xpBudget
+	}
+
+	if Psize < minTablePartySize || Psize > maxTablePartySize {
+		resp.Warnings = append(resp.Warnings, structs.EncounterWarning{
+			Code:    "party_size_extrapolated",
+			Message: fmt.Sprintf("budgets for parties outside %d-%d characters are extrapolated from the per-character adjustment; consider splitting large groups or adding creatures rather than raising their level", minTablePartySize, maxTablePartySize),
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+func TestCalculatexpBudget(t *testing.T) {
+	router := NewRouter(config.Config{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/budget?psize=5&difficulty=Severe", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body budgetResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+	if body.Budget == nil || *body.Budget != 150 {
+		t.Errorf("Expected severe budget 150, got %v", body.Budget)
+	}
+	if len(body.Tiers) != 5 {
+		t.Fatalf("Expected 5 tiers, got %d", len(body.Tiers))
+	}
+	if body.Tiers[0].Threat != "trivial" || body.Tiers[0].Budget != 50 || body.Tiers[0].CharacterAdjustment != 10 {
+		t.Errorf("Unexpected trivial tier %+v", body.Tiers[0])
+	}
+	if len(body.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", body.Warnings)
+	}
+}
+
+func TestCalculatexpBudgetLargeParty(t *testing.T) {
+	router := NewRouter(config.Config{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/budget?psize=10", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body budgetResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+	if body.Tiers[4].Budget != 400 {
+		t.Errorf("Expected extreme budget 400, got %d", body.Tiers[4].Budget)
+	}
+	if len(body.Warnings) != 1 || body.Warnings[0].Code != "party_size_extrapolated" {
+		t.Errorf("Expected party_size_extrapolated warning, got %v", body.Warnings)
+	}
+}
+
+func TestCalculatexpBudgetInvalid(t *testing.T) {
+	tests := []struct {
+		target string
+		code   string
+	}{
+		{"/v1/budget?psize=abc", "invalid_party_size"},
+		{"/v1/budget?psize=0", "invalid_party_size"},
+		{"/v1/budget?psize=4&difficulty=impossible", "invalid_difficulty"},
+	}
+	router := NewRouter(config.Config{}, nil)
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", test.target, rec.Code)
+			continue
+		}
+		var body errorBody
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Expected JSON error body, got %v", err)
+		}
+		if body.Error.Code != test.code {
+			t.Errorf("%s: expected code %s, got %s", test.target, test.code, body.Error.Code)
+		}
+	}
+}
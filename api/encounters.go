@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/utils"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+const maxRequestBody = 1 << 20
+
+type evaluateRequest struct {
+	PartyLevel  int                      `json:"party_level"`
+	PartySize   int                      `json:"party_size"`
+	PartyLevels []int                    `json:"party_levels"`
+	Monsters    []structs.EncounterEntry `json:"monsters"`
+	Hazards     []structs.EncounterEntry `json:"hazards"`
+}
+
+// partyFromRequest builds the party from either the level of each character
+// or a single party level and size.
+func partyFromRequest(level int, size int, levels []int) (utils.Party, *apiError) {
+	if len(levels) > 0 {
+		if len(levels) > maxPartySize {
+			return utils.Party{}, &apiError{Code: "invalid_party_size", Message: fmt.Sprintf("party_levels may list at most %d characters", maxPartySize)}
+		}
+		for _, l := range levels {
+			if l < 1 || l > 20 {
+				return utils.Party{}, &apiError{Code: "invalid_party_level", Message: "every entry in party_levels must be between 1 and 20"}
+			}
+		}
+		party, err := utils.NewMixedParty(levels)
+		if err != nil {
+			return utils.Party{}, &apiError{Code: "invalid_party_level", Message: err.Error()}
+		}
+		return party, nil
+	}
+	if level < 1 || level > 20 {
+		return utils.Party{}, &apiError{Code: "invalid_party_level", Message: "party_level must be between 1 and 20"}
+	}
+	if size < 1 || size > maxPartySize {
+		return utils.Party{}, &apiError{Code: "invalid_party_size", Message: fmt.Sprintf("party_size must be between 1 and %d", maxPartySize)}
+	}
+	return utils.NewParty(level, size), nil
+}
+
+// decodeBody strictly decodes a JSON request body into v.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// EvaluateEncounter rates a proposed encounter against the party's budget.
+func EvaluateEncounter(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req evaluateRequest
+		if err := decodeBody(w, r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+			return
+		}
+		party, perr := partyFromRequest(req.PartyLevel, req.PartySize, req.PartyLevels)
+		if perr != nil {
+			writeError(w, http.StatusBadRequest, perr.Code, perr.Message)
+			return
+		}
+		for _, hazard := range req.Hazards {
+			hazard.Kind = utils.EntryHazard
+			req.Monsters = append(req.Monsters, hazard)
+		}
+		for i := range req.Monsters {
+			if req.Monsters[i].Count == 0 {
+				req.Monsters[i].Count = 1
+			}
+		}
+
+		queries := writeMonsters.New(cfg.DBPool)
+		eval, err := utils.EvaluateEncounter(r.Context(), queries, party, req.Monsters)
+		if errors.Is(err, utils.ErrMonsterNotFound) {
+			writeError(w, http.StatusNotFound, "monster_not_found", err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidEncounter) {
+			writeError(w, http.StatusBadRequest, "invalid_encounter", err.Error())
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to evaluate encounter", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to evaluate encounter")
+			return
+		}
+		writeJSON(w, http.StatusOK, eval)
+	}
+}
+
+type generateRequest struct {
+	PartyLevel   int      `json:"party_level"`
+	PartySize    int      `json:"party_size"`
+	PartyLevels  []int    `json:"party_levels"`
+	Difficulty   string   `json:"difficulty"`
+	Traits       []string `json:"traits"`
+	Rarity       string   `json:"rarity"`
+	Size         string   `json:"size"`
+	Source       string   `json:"source"`
+	MaxCreatures int      `json:"max_creatures"`
+	MustInclude  *int32   `json:"must_include"`
+	Suggestions  int      `json:"suggestions"`
+	Tolerance    *int     `json:"tolerance"`
+	Seed         *uint64  `json:"seed"`
+}
+
+type generateResponse struct {
+	Seed        uint64                        `json:"seed"`
+	Suggestions []structs.EncounterSuggestion `json:"suggestions"`
+}
+
+const maxSuggestions = 10
+
+// GenerateEncounter builds candidate encounters from the database that fill
+// the party's XP budget for the requested difficulty.
+func GenerateEncounter(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req generateRequest
+		if err := decodeBody(w, r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+			return
+		}
+		party, perr := partyFromRequest(req.PartyLevel, req.PartySize, req.PartyLevels)
+		if perr != nil {
+			writeError(w, http.StatusBadRequest, perr.Code, perr.Message)
+			return
+		}
+		req.Difficulty = strings.ToLower(req.Difficulty)
+		if !slices.Contains(utils.ThreatTiers, req.Difficulty) {
+			writeError(w, http.StatusBadRequest, "invalid_difficulty", "difficulty must be one of "+strings.Join(utils.ThreatTiers, ", "))
+			return
+		}
+		if req.Suggestions > maxSuggestions {
+			writeError(w, http.StatusBadRequest, "invalid_suggestions", fmt.Sprintf("suggestions must be at most %d", maxSuggestions))
+			return
+		}
+
+		opts := utils.GeneratorOptions{
+			Party:        party,
+			Difficulty:   req.Difficulty,
+			Tolerance:    utils.DefaultGeneratorTolerance,
+			MaxCreatures: req.MaxCreatures,
+			Suggestions:  req.Suggestions,
+			Seed:         rand.Uint64(),
+		}
+		if req.Tolerance != nil {
+			opts.Tolerance = *req.Tolerance
+		}
+		if req.Seed != nil {
+			opts.Seed = *req.Seed
+		}
+
+		queries := writeMonsters.New(cfg.DBPool)
+		if req.MustInclude != nil {
+			rows, err := queries.GetMonsterLevelsByIDs(r.Context(), []int32{*req.MustInclude})
+			if err != nil {
+				logger.Log.Error("failed to load must include monster", "err", err)
+				writeError(w, http.StatusInternalServerError, "internal_error", "unable to generate encounter")
+				return
+			}
+			if len(rows) == 0 {
+				writeError(w, http.StatusNotFound, "monster_not_found", fmt.Sprintf("monster ID %d does not exist", *req.MustInclude))
+				return
+			}
+			opts.MustInclude = &utils.EncounterCandidate{
+				MonsterID: rows[0].ID,
+				Name:      rows[0].Name,
+				Level:     int(rows[0].Level.Int32),
+			}
+		}
+
+		rows, err := queries.GetEncounterCandidates(r.Context(), writeMonsters.GetEncounterCandidatesParams{
+			MinLevel:  int32(party.Level + utils.MinCreatureLevelDifference),
+			MaxLevel:  int32(party.Level + utils.MaxCreatureLevelDifference),
+			TraitsAny: req.Traits,
+			Rarity:    utils.NewText(req.Rarity),
+			Size:      utils.NewText(req.Size),
+			Source:    utils.NewText(req.Source),
+		})
+		if err != nil {
+			logger.Log.Error("failed to load encounter candidates", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to generate encounter")
+			return
+		}
+		candidates := make([]utils.EncounterCandidate, 0, len(rows))
+		for _, row := range rows {
+			candidates = append(candidates, utils.EncounterCandidate{
+				MonsterID: row.ID,
+				Name:      row.Name,
+				Level:     int(row.Level.Int32),
+			})
+		}
+
+		suggestions, err := utils.GenerateEncounters(candidates, opts)
+		switch {
+		case errors.Is(err, utils.ErrNoEncounterFound):
+			writeError(w, http.StatusUnprocessableEntity, "no_encounter_found", err.Error())
+		case errors.Is(err, utils.ErrInvalidEncounter):
+			writeError(w, http.StatusBadRequest, "invalid_encounter", err.Error())
+		case err != nil:
+			logger.Log.Error("failed to generate encounter", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to generate encounter")
+		default:
+			writeJSON(w, http.StatusOK, generateResponse{Seed: opts.Seed, Suggestions: suggestions})
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetHazard returns the full stat block for a single hazard.
+func GetHazard(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_id", "hazard id must be an integer")
+			return
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		hazard, err := queries.GetFullHazardByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "hazard_not_found", "no hazard exists with that id")
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to load hazard", "id", id, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load hazard")
+			return
+		}
+		writeRawJSON(w, http.StatusOK, hazard)
+	}
+}
+
+type hazardSummary struct {
+	ID      int32           `json:"id"`
+	Name    string          `json:"name"`
+	Level   int32           `json:"level"`
+	Complex bool            `json:"complex"`
+	Rarity  string          `json:"rarity"`
+	Stealth int32           `json:"stealth"`
+	Source  string          `json:"source"`
+	Traits  json.RawMessage `json:"traits"`
+}
+
+type hazardSearchResponse struct {
+	Results []hazardSummary `json:"results"`
+	Total   int64           `json:"total"`
+	Limit   int32           `json:"limit"`
+	Offset  int32           `json:"offset"`
+}
+
+func parseHazardSearchParams(r *http.Request) (writeMonsters.SearchHazardsParams, error) {
+	params := writeMonsters.SearchHazardsParams{
+		Name:      optionalText(r, "name"),
+		Source:    optionalText(r, "source"),
+		PageLimit: defaultSearchLimit,
+	}
+	var err error
+	if params.MinLevel, err = optionalInt(r, "min_level"); err != nil {
+		return params, err
+	}
+	if params.MaxLevel, err = optionalInt(r, "max_level"); err != nil {
+		return params, err
+	}
+	if params.MinLevel.Valid && params.MaxLevel.Valid && params.MinLevel.Int32 > params.MaxLevel.Int32 {
+		return params, &paramError{Param: "min_level", Message: "must not be greater than max_level"}
+	}
+	if raw := strings.TrimSpace(r.URL.Query().Get("complex")); raw != "" {
+		complex, err := strconv.ParseBool(raw)
+		if err != nil {
+			return params, &paramError{Param: "complex", Message: "must be true or false"}
+		}
+		params.IsComplex = pgtype.Bool{Bool: complex, Valid: true}
+	}
+
+	limit, err := optionalInt(r, "limit")
+	if err != nil {
+		return params, err
+	}
+	if limit.Valid {
+		if limit.Int32 < 1 || limit.Int32 > maxSearchLimit {
+			return params, &paramError{Param: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxSearchLimit)}
+		}
+		params.PageLimit = limit.Int32
+	}
+	offset, err := optionalInt(r, "offset")
+	if err != nil {
+		return params, err
+	}
+	if offset.Valid {
+		if offset.Int32 < 0 {
+			return params, &paramError{Param: "offset", Message: "must not be negative"}
+		}
+		params.PageOffset = offset.Int32
+	}
+	return params, nil
+}
+
+// SearchHazards lists hazards filtered by name, level range and complexity.
+func SearchHazards(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseHazardSearchParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		rows, err := queries.SearchHazards(r.Context(), params)
+		if err != nil {
+			logger.Log.Error("failed to search hazards", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to search hazards")
+			return
+		}
+
+		resp := hazardSearchResponse{
+			Results: make([]hazardSummary, 0, len(rows)),
+			Limit:   params.PageLimit,
+			Offset:  params.PageOffset,
+		}
+		for _, row := range rows {
+			resp.Total = row.TotalCount
+			traits := json.RawMessage(row.Traits)
+			if len(traits) == 0 {
+				traits = json.RawMessage("[]")
+			}
+			resp.Results = append(resp.Results, hazardSummary{
+				ID:      row.ID,
+				Name:    row.Name,
+				Level:   row.Level.Int32,
+				Complex: row.IsComplex,
+				Rarity:  row.TraitsRarity.String,
+				Stealth: row.StealthValue.Int32,
+				Source:  row.Source,
+				Traits:  traits,
+			})
+		}
+		// Past the last match there are no rows to carry the total.
+		if len(rows) == 0 && params.PageOffset > 0 {
+			resp.Total, err = queries.CountHazards(r.Context(), writeMonsters.CountHazardsParams{
+				Name:      params.Name,
+				MinLevel:  params.MinLevel,
+				MaxLevel:  params.MaxLevel,
+				IsComplex: params.IsComplex,
+				Source:    params.Source,
+			})
+			if err != nil {
+				logger.Log.Error("failed to count hazards", "err", err)
+				writeError(w, http.StatusInternalServerError, "internal_error", "unable to search hazards")
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
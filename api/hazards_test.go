@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseHazardSearchParams(t *testing.T) {
+	req := httptest.NewRequest("GET", "/v1/hazards?min_level=1&max_level=4&complex=true", nil)
+	params, err := parseHazardSearchParams(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if params.MinLevel.Int32 != 1 || params.MaxLevel.Int32 != 4 {
+		t.Errorf("Expected level range 1-4, got %d-%d", params.MinLevel.Int32, params.MaxLevel.Int32)
+	}
+	if !params.IsComplex.Valid || !params.IsComplex.Bool {
+		t.Errorf("Expected complex filter true, got %+v", params.IsComplex)
+	}
+	if params.PageLimit != defaultSearchLimit {
+		t.Errorf("Expected default limit %d, got %d", defaultSearchLimit, params.PageLimit)
+	}
+
+	for _, query := range []string{"complex=maybe", "min_level=5&max_level=2", "limit=0"} {
+		if _, err := parseHazardSearchParams(httptest.NewRequest("GET", "/v1/hazards?"+query, nil)); err == nil {
+			t.Errorf("Expected an error for %s", query)
+		}
+	}
+}
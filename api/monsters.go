@@ -0,0 +1,280 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/utils"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetMonster returns the full aggregated stat block for a single monster.
+func GetMonster(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_id", "monster id must be an integer")
+			return
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		monster, err := queries.GetFullMonsterByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "monster_not_found", "no monster exists with that id")
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to load monster", "id", id, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load monster")
+			return
+		}
+		writeRawJSON(w, http.StatusOK, monster)
+	}
+}
+
+// GetTemplatedMonster returns a monster with the elite or weak template
+// applied, in the same shape as GetMonster plus the template and the change
+// in level.
+func GetTemplatedMonster(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_id", "monster id must be an integer")
+			return
+		}
+		template := chi.URLParam(r, "template")
+		if template != utils.TemplateElite && template != utils.TemplateWeak {
+			writeError(w, http.StatusBadRequest, "invalid_template", utils.ErrInvalidTemplate.Error())
+			return
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		monster, err := queries.GetFullMonsterByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "monster_not_found", "no monster exists with that id")
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to load monster", "id", id, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load monster")
+			return
+		}
+		adjusted, err := utils.TemplateMonsterDocument(monster, template)
+		if err != nil {
+			logger.Log.Error("failed to apply template", "id", id, "template", template, "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to apply the template")
+			return
+		}
+		writeRawJSON(w, http.StatusOK, adjusted)
+	}
+}
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// monsterSummary is the trimmed row returned by the search endpoint. Clients
+// follow up with GET /v1/monsters/{id} for the full stat block.
+type monsterSummary struct {
+	ID     int32           `json:"id"`
+	Name   string          `json:"name"`
+	Level  int32           `json:"level"`
+	Rarity string          `json:"rarity"`
+	Size   string          `json:"size"`
+	HP     int32           `json:"hp"`
+	AC     int32           `json:"ac"`
+	Source string          `json:"source"`
+	Traits json.RawMessage `json:"traits"`
+}
+
+type searchResponse struct {
+	Results []monsterSummary `json:"results"`
+	Total   int64            `json:"total"`
+	Limit   int32            `json:"limit"`
+	Offset  int32            `json:"offset"`
+}
+
+// paramError is returned by the query string parsers so handlers can report
+// which parameter was rejected.
+type paramError struct {
+	Param   string
+	Message string
+}
+
+func (e *paramError) Error() string {
+	return e.Param + ": " + e.Message
+}
+
+func optionalText(r *http.Request, key string) pgtype.Text {
+	return utils.NewText(strings.TrimSpace(r.URL.Query().Get(key)))
+}
+
+func optionalInt(r *http.Request, key string) (pgtype.Int4, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return pgtype.Int4{}, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return pgtype.Int4{}, &paramError{Param: key, Message: "must be an integer"}
+	}
+	return utils.NewInt4(value), nil
+}
+
+// listParam accepts both repeated keys (?trait=a&trait=b) and comma separated
+// values (?trait=a,b).
+func listParam(r *http.Request, key string) []string {
+	var values []string
+	for _, raw := range r.URL.Query()[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func parseSearchParams(r *http.Request) (writeMonsters.SearchMonstersParams, error) {
+	params := writeMonsters.SearchMonstersParams{
+		Name:      optionalText(r, "name"),
+		Rarity:    optionalText(r, "rarity"),
+		Size:      optionalText(r, "size"),
+		Immunity:  optionalText(r, "immunity"),
+		Weakness:  optionalText(r, "weakness"),
+		Movement:  optionalText(r, "movement"),
+		Source:    optionalText(r, "source"),
+		SortBy:    "name",
+		PageLimit: defaultSearchLimit,
+	}
+	var err error
+	if params.MinLevel, err = optionalInt(r, "min_level"); err != nil {
+		return params, err
+	}
+	if params.MaxLevel, err = optionalInt(r, "max_level"); err != nil {
+		return params, err
+	}
+	if params.MinLevel.Valid && params.MaxLevel.Valid && params.MinLevel.Int32 > params.MaxLevel.Int32 {
+		return params, &paramError{Param: "min_level", Message: "must not be greater than max_level"}
+	}
+
+	traits := listParam(r, "trait")
+	switch strings.ToLower(r.URL.Query().Get("trait_match")) {
+	case "", "any":
+		params.TraitsAny = traits
+	case "all":
+		params.TraitsAll = traits
+	default:
+		return params, &paramError{Param: "trait_match", Message: "must be 'any' or 'all'"}
+	}
+
+	if sort := strings.ToLower(r.URL.Query().Get("sort")); sort != "" {
+		switch sort {
+		case "level", "name", "hp":
+			params.SortBy = sort
+		default:
+			return params, &paramError{Param: "sort", Message: "must be one of level, name, hp"}
+		}
+	}
+	switch strings.ToLower(r.URL.Query().Get("order")) {
+	case "", "asc":
+	case "desc":
+		params.SortDesc = true
+	default:
+		return params, &paramError{Param: "order", Message: "must be 'asc' or 'desc'"}
+	}
+
+	limit, err := optionalInt(r, "limit")
+	if err != nil {
+		return params, err
+	}
+	if limit.Valid {
+		if limit.Int32 < 1 || limit.Int32 > maxSearchLimit {
+			return params, &paramError{Param: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxSearchLimit)}
+		}
+		params.PageLimit = limit.Int32
+	}
+	offset, err := optionalInt(r, "offset")
+	if err != nil {
+		return params, err
+	}
+	if offset.Valid {
+		if offset.Int32 < 0 {
+			return params, &paramError{Param: "offset", Message: "must not be negative"}
+		}
+		params.PageOffset = offset.Int32
+	}
+	return params, nil
+}
+
+// SearchMonsters combines every monster filter into a single paginated query.
+func SearchMonsters(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseSearchParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		rows, err := queries.SearchMonsters(r.Context(), params)
+		if err != nil {
+			logger.Log.Error("failed to search monsters", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to search monsters")
+			return
+		}
+
+		resp := searchResponse{
+			Results: make([]monsterSummary, 0, len(rows)),
+			Limit:   params.PageLimit,
+			Offset:  params.PageOffset,
+		}
+		for _, row := range rows {
+			resp.Total = row.TotalCount
+			traits := json.RawMessage(row.Traits)
+			if len(traits) == 0 {
+				traits = json.RawMessage("[]")
+			}
+			resp.Results = append(resp.Results, monsterSummary{
+				ID:     row.ID,
+				Name:   row.Name,
+				Level:  row.Level.Int32,
+				Rarity: row.TraitsRarity.String,
+				Size:   row.TraitsSize.String,
+				HP:     row.HpValue.Int32,
+				AC:     row.AcValue.Int32,
+				Source: row.Source,
+				Traits: traits,
+			})
+		}
+		// Past the last match there are no rows to carry the total.
+		if len(rows) == 0 && params.PageOffset > 0 {
+			resp.Total, err = queries.CountMonsters(r.Context(), writeMonsters.CountMonstersParams{
+				Name:      params.Name,
+				MinLevel:  params.MinLevel,
+				MaxLevel:  params.MaxLevel,
+				TraitsAny: params.TraitsAny,
+				TraitsAll: params.TraitsAll,
+				Rarity:    params.Rarity,
+				Size:      params.Size,
+				Immunity:  params.Immunity,
+				Weakness:  params.Weakness,
+				Movement:  params.Movement,
+				Source:    params.Source,
+			})
+			if err != nil {
+				logger.Log.Error("failed to count monsters", "err", err)
+				writeError(w, http.StatusInternalServerError, "internal_error", "unable to search monsters")
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
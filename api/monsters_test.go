@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseSearchParams(t *testing.T) {
+	req := httptest.NewRequest("GET", "/v1/monsters?name=dragon&min_level=-1&max_level=10&trait=fire,dragon&trait_match=all&sort=level&order=desc&limit=10&offset=20", nil)
+	params, err := parseSearchParams(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if params.Name.String != "dragon" || !params.Name.Valid {
+		t.Errorf("Expected name 'dragon', got %+v", params.Name)
+	}
+	if params.MinLevel.Int32 != -1 || params.MaxLevel.Int32 != 10 {
+		t.Errorf("Expected level range -1..10, got %d..%d", params.MinLevel.Int32, params.MaxLevel.Int32)
+	}
+	if len(params.TraitsAll) != 2 || params.TraitsAll[0] != "fire" || params.TraitsAll[1] != "dragon" {
+		t.Errorf("Expected all traits [fire dragon], got %v", params.TraitsAll)
+	}
+	if params.TraitsAny != nil {
+		t.Errorf("Expected no any traits, got %v", params.TraitsAny)
+	}
+	if params.SortBy != "level" || !params.SortDesc {
+		t.Errorf("Expected sort level desc, got %s desc=%v", params.SortBy, params.SortDesc)
+	}
+	if params.PageLimit != 10 || params.PageOffset != 20 {
+		t.Errorf("Expected limit 10 offset 20, got %d %d", params.PageLimit, params.PageOffset)
+	}
+	if params.Rarity.Valid {
+		t.Errorf("Expected rarity to be unset, got %+v", params.Rarity)
+	}
+}
+
+func TestParseSearchParamsInvalid(t *testing.T) {
+	tests := []string{
+		"/v1/monsters?min_level=abc",
+		"/v1/monsters?min_level=5&max_level=2",
+		"/v1/monsters?trait_match=some",
+		"/v1/monsters?sort=ac",
+		"/v1/monsters?order=sideways",
+		"/v1/monsters?limit=0",
+		"/v1/monsters?limit=1000",
+		"/v1/monsters?offset=-1",
+	}
+	for _, target := range tests {
+		req := httptest.NewRequest("GET", target, nil)
+		if _, err := parseSearchParams(req); err == nil {
+			t.Errorf("Expected error for %s", target)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Burtcam/encounter-builder-backend/logger"
+)
+
+// errorBody is the JSON envelope returned for every non 2xx response.
+type errorBody struct {
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeJSON marshals v and writes it with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Log.Error("failed to encode response", "err", err)
+	}
+}
+
+// writeRawJSON writes a document that is already JSON encoded, such as the
+// row_to_json output of the monster queries.
+func writeRawJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		logger.Log.Error("failed to write response", "err", err)
+	}
+}
+
+// writeError writes a JSON error body with a machine readable code.
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, errorBody{Error: apiError{Code: code, Message: message}})
+}
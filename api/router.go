@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// SyncTrigger starts a sync on demand and returns the ids of its runs, one
+// per data source with the pf2e system first. It returns
+// utils.ErrSyncInProgress while another sync is running.
+type SyncTrigger interface {
+	Trigger() ([]int32, error)
+}
+
+// NewRouter builds the versioned HTTP API. syncs may be nil, in which case
+// syncs cannot be started through the API.
+func NewRouter(cfg config.Config, syncs SyncTrigger) http.Handler {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Recoverer)
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "route_not_found", "no route matches "+r.URL.Path)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" is not allowed on "+r.URL.Path)
+	})
+
+	r.Get("/calculatebudget", CalculatexpBudget)
+
+	r.Route("/v1", func(r chi.Router) {
+		r.Get("/budget", CalculatexpBudget)
+		r.Get("/data_version", GetDataVersion(cfg))
+		r.Route("/monsters", func(r chi.Router) {
+			r.Get("/", SearchMonsters(cfg))
+			r.Get("/{id}", GetMonster(cfg))
+			r.Get("/{id}/{template}", GetTemplatedMonster(cfg))
+		})
+		r.Route("/hazards", func(r chi.Router) {
+			r.Get("/", SearchHazards(cfg))
+			r.Get("/{id}", GetHazard(cfg))
+		})
+		r.Route("/encounters", func(r chi.Router) {
+			r.Post("/evaluate", EvaluateEncounter(cfg))
+			r.Post("/generate", GenerateEncounter(cfg))
+		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(requireAdmin(cfg.ADMIN_TOKEN))
+			r.Post("/sync", TriggerSync(syncs))
+			r.Get("/sync/runs", ListSyncRuns(cfg))
+			r.Get("/sync/runs/{id}", GetSyncRun(cfg))
+			r.Get("/quarantine", ListQuarantine(cfg))
+			r.Get("/quarantine/{id}", GetQuarantinedItem(cfg))
+			r.Get("/upstream/releases", ListReleases(cfg))
+		})
+	})
+	return r
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+func TestGetMonsterInvalidID(t *testing.T) {
+	router := NewRouter(config.Config{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/monsters/not-a-number", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	var body errorBody
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON error body, got %v", err)
+	}
+	if body.Error.Code != "invalid_id" {
+		t.Errorf("Expected code 'invalid_id', got '%s'", body.Error.Code)
+	}
+}
+
+func TestUnknownRouteReturnsJSON(t *testing.T) {
+	router := NewRouter(config.Config{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/nothing-here", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got '%s'", ct)
+	}
+}
+
+func TestGetTemplatedMonsterInvalidTemplate(t *testing.T) {
+	router := NewRouter(config.Config{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/monsters/1/mighty", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	var body errorBody
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON error body, got %v", err)
+	}
+	if body.Error.Code != "invalid_template" {
+		t.Errorf("Expected code 'invalid_template', got '%s'", body.Error.Code)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/utils"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/jackc/pgx/v5"
+)
+
+type dataVersion struct {
+	Source string `json:"source"`
+	// Version is the release tag when the last sync was pinned to one,
+	// otherwise the upstream commit.
+	Version       string    `json:"version"`
+	Release       string    `json:"release,omitempty"`
+	SourceVersion string    `json:"source_version"`
+	LoadedAt      time.Time `json:"loaded_at"`
+	// Monsters counts the active monsters by the version they were loaded
+	// from, which differ when some packs were imported separately.
+	Monsters []versionCount `json:"monsters"`
+}
+
+type versionCount struct {
+	Version  *string `json:"version"`
+	Monsters int64   `json:"monsters"`
+}
+
+type upstreamRelease struct {
+	Tag         string    `json:"tag"`
+	Name        string    `json:"name"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type releasesResponse struct {
+	Results []upstreamRelease `json:"results"`
+	// Selected is SYNC_RELEASE, empty when syncs follow REPO_URL as given.
+	Selected string `json:"selected,omitempty"`
+}
+
+// GetDataVersion reports which upstream data a data source of the bestiary
+// was synced from, the pf2e system unless the source parameter names
+// another.
+func GetDataVersion(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := optionalText(r, "source").String
+		if name == "" {
+			name = utils.DefaultSource
+		}
+		queries := writeMonsters.New(cfg.DBPool)
+		row, err := queries.GetDataVersion(r.Context(), name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "data_version_unknown", "source "+name+" has not been synced since versions were recorded")
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to load data version", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load data version")
+			return
+		}
+		counts, err := queries.ListMonsterDataVersions(r.Context(), name)
+		if err != nil {
+			logger.Log.Error("failed to count monsters by data version", "err", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "unable to load data version")
+			return
+		}
+		source := utils.SyncSource{Version: row.SourceVersion, Release: row.Release.String}
+		resp := dataVersion{
+			Source:        row.Source,
+			Version:       source.DataVersion(),
+			Release:       row.Release.String,
+			SourceVersion: row.SourceVersion,
+			LoadedAt:      row.LoadedAt.Time,
+			Monsters:      make([]versionCount, 0, len(counts)),
+		}
+		for _, count := range counts {
+			c := versionCount{Monsters: count.Monsters}
+			if count.DataVersion.Valid {
+				c.Version = &count.DataVersion.String
+			}
+			resp.Monsters = append(resp.Monsters, c)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// ListReleases lists the upstream releases a sync can be pinned to with
+// SYNC_RELEASE.
+func ListReleases(cfg config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		releases, err := utils.ListUpstreamReleases(r.Context(), cfg)
+		if errors.Is(err, utils.ErrNotGitHubRepo) {
+			writeError(w, http.StatusServiceUnavailable, "releases_unavailable", err.Error())
+			return
+		}
+		if err != nil {
+			logger.Log.Error("failed to list upstream releases", "err", err)
+			writeError(w, http.StatusBadGateway, "upstream_error", "unable to list upstream releases")
+			return
+		}
+		resp := releasesResponse{Results: make([]upstreamRelease, 0, len(releases)), Selected: cfg.SYNC_RELEASE}
+		for _, release := range releases {
+			resp.Results = append(resp.Results, upstreamRelease{
+				Tag:         release.Tag,
+				Name:        release.Name,
+				Prerelease:  release.Prerelease,
+				PublishedAt: release.PublishedAt,
+			})
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
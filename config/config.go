@@ -3,6 +3,10 @@ package config
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/Burtcam/encounter-builder-backend/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,6 +18,94 @@ type Config struct {
 	REPO_URL      string
 	DB_CONNECTION string
 	DBPool        *pgxpool.Pool
+	// SYNC_BATCH_SIZE is how many monsters a sync writes per transaction.
+	SYNC_BATCH_SIZE int
+	// SYNC_PARSE_WORKERS and SYNC_WRITE_WORKERS bound how many files a sync
+	// parses and how many transactions it writes at once.
+	SYNC_PARSE_WORKERS int
+	SYNC_WRITE_WORKERS int
+	// SYNC_SCHEDULE is a standard five field cron expression evaluated in
+	// SYNC_TIMEZONE, an IANA zone name.
+	SYNC_SCHEDULE string
+	SYNC_TIMEZONE string
+	// ADMIN_TOKEN is the bearer token the /v1/admin endpoints require. The
+	// admin endpoints refuse every request while it is unset.
+	ADMIN_TOKEN string
+	// SYNC_ARCHIVE_PATH is where a sync downloads REPO_URL. The archive is
+	// streamed from there and removed once the sync ends.
+	SYNC_ARCHIVE_PATH string
+	// SYNC_ARCHIVE_SHA256 is the hex checksum the download must match, for
+	// a REPO_URL pinned to one commit. When unset only the size the server
+	// reports is checked.
+	SYNC_ARCHIVE_SHA256 string
+	// SYNC_DOWNLOAD_ATTEMPTS bounds how often a failed download is retried
+	// and SYNC_DOWNLOAD_TIMEOUT how long it may take in total, retries
+	// included.
+	SYNC_DOWNLOAD_ATTEMPTS int
+	SYNC_DOWNLOAD_TIMEOUT  time.Duration
+	// SYNC_RELEASE pins syncs to a release tag of the repository REPO_URL
+	// points at, or follows its latest stable release when set to "latest".
+	// When unset REPO_URL is downloaded as given.
+	SYNC_RELEASE string
+	// SYNC_SOURCES registers more Foundry repositories to sync after the
+	// pf2e system REPO_URL points at, as a JSON array such as
+	// [{"name": "sf2e", "repo_url": "https://api.github.com/repos/OWNER/REPO/tarball", "release": "latest", "packs": ["*-bestiary"]}].
+	// Each entry may also set sha256 like SYNC_ARCHIVE_SHA256.
+	SYNC_SOURCES string
+}
+
+// LiveSchema holds the bestiary tables the API reads from. Syncs build a new
+// generation in a staging schema and swap it in under this name.
+const LiveSchema = "bestiary"
+
+// NewPool opens a connection pool whose search_path starts at schema, so the
+// generated queries resolve to that generation of the bestiary tables.
+func NewPool(ctx context.Context, connString string, schema string) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
+
+// stringEnv reads a string from the environment, falling back to def when
+// the variable is unset.
+func stringEnv(key string, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// positiveIntEnv reads a positive integer from the environment, falling back
+// to def when the variable is unset or invalid.
+func positiveIntEnv(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		logger.Log.Warn("Ignoring invalid setting", "key", key, "value", raw, "default", def)
+		return def
+	}
+	return value
+}
+
+// durationEnv reads a positive duration such as "15m" from the environment,
+// falling back to def when the variable is unset or invalid.
+func durationEnv(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		logger.Log.Warn("Ignoring invalid setting", "key", key, "value", raw, "default", def)
+		return def
+	}
+	return value
 }
 
 func Load() *Config {
@@ -23,15 +115,27 @@ func Load() *Config {
 	}
 
 	cfg := Config{
-		GH_TOKEN:      os.Getenv("GH_TOKEN"),
-		REPO_URL:      os.Getenv("REPO_URL"),
-		DB_CONNECTION: os.Getenv("DB_CONNECTION_STRING"),
+		GH_TOKEN:               os.Getenv("GH_TOKEN"),
+		REPO_URL:               os.Getenv("REPO_URL"),
+		DB_CONNECTION:          os.Getenv("DB_CONNECTION_STRING"),
+		SYNC_BATCH_SIZE:        positiveIntEnv("SYNC_BATCH_SIZE", 1),
+		SYNC_PARSE_WORKERS:     positiveIntEnv("SYNC_PARSE_WORKERS", runtime.NumCPU()),
+		SYNC_WRITE_WORKERS:     positiveIntEnv("SYNC_WRITE_WORKERS", 4),
+		SYNC_SCHEDULE:          stringEnv("SYNC_SCHEDULE", "0 3 * * 2"),
+		SYNC_TIMEZONE:          stringEnv("SYNC_TIMEZONE", "America/Los_Angeles"),
+		ADMIN_TOKEN:            os.Getenv("ADMIN_TOKEN"),
+		SYNC_ARCHIVE_PATH:      stringEnv("SYNC_ARCHIVE_PATH", filepath.Join(os.TempDir(), "repo_archive.tar.gz")),
+		SYNC_ARCHIVE_SHA256:    os.Getenv("SYNC_ARCHIVE_SHA256"),
+		SYNC_DOWNLOAD_ATTEMPTS: positiveIntEnv("SYNC_DOWNLOAD_ATTEMPTS", 5),
+		SYNC_DOWNLOAD_TIMEOUT:  durationEnv("SYNC_DOWNLOAD_TIMEOUT", 15*time.Minute),
+		SYNC_RELEASE:           os.Getenv("SYNC_RELEASE"),
+		SYNC_SOURCES:           os.Getenv("SYNC_SOURCES"),
 	}
 	logger.Log.Info("Configuration succesfully Loaded")
 	ctx := context.Background()
 	logger.Log.Debug("Creating DB Connection Pool")
 	// Create connection pool
-	pool, err := pgxpool.New(ctx, cfg.DB_CONNECTION)
+	pool, err := NewPool(ctx, cfg.DB_CONNECTION, LiveSchema)
 	if err != nil {
 		logger.Log.Error("failed to create DB pool: %v", "err", err)
 		return nil
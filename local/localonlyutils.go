@@ -1,6 +1,8 @@
 package localonlyutils
 
 import (
+	"time"
+
 	"github.com/Burtcam/encounter-builder-backend/config"
 	"github.com/Burtcam/encounter-builder-backend/logger"
 	"github.com/Burtcam/encounter-builder-backend/utils"
@@ -13,8 +15,9 @@ func LocalDataLoad(cfg config.Config) error {
 		"files/foundryvtt-pf2e-4cbdaa3/packs/age-of-ashes-bestiary/book-4-fires-of-the-haunted-city/saggorak-poltergeist.json",
 		"files/foundryvtt-pf2e-4cbdaa3/packs/age-of-ashes-bestiary/book-5-against-the-scarlet-triad/scarlet-triad-enforcer.json",
 		"files/foundryvtt-pf2e-4cbdaa3/packs/age-of-ashes-bestiary/book-4-fires-of-the-haunted-city/king-harral.json"}
+	seenAt := time.Now()
 	for i := range len(fileList) {
-		err := utils.LoadEachJSON(cfg, fileList[i])
+		err := utils.LoadEachJSON(cfg, fileList[i], seenAt)
 		if err != nil {
 			logger.Log.Error("Failed to write file %s. Err: %v", fileList[i], err)
 		}
@@ -2,55 +2,102 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"strconv"
-
+	"github.com/Burtcam/encounter-builder-backend/api"
 	"github.com/Burtcam/encounter-builder-backend/config"
 	"github.com/Burtcam/encounter-builder-backend/logger"
 	"github.com/Burtcam/encounter-builder-backend/utils"
-	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
 )
 
-func CalculatexpBudget(w http.ResponseWriter, r *http.Request) {
-	psizeStr := r.URL.Query().Get("psize")
-	Psize, err := strconv.Atoi(psizeStr)
-	if err != nil {
-		http.Error(w, "Invalid psize parameter", http.StatusBadRequest)
-		return
-	}
-	difficulty := r.URL.Query().Get("difficulty")
-
-	xpBudget, err := utils.GetXpBudget(difficulty, Psize)
+// shutdownTimeout bounds how long in-flight requests and a running sync get
+// to finish once the process is told to stop.
+const shutdownTimeout = 30 * time.Second
 
-	if err != nil {
-		logger.Log.Error("unable to calculate xp budget %w", err)
+// serve runs the API until ctx is cancelled, then drains in-flight requests.
+func serve(ctx context.Context, cfg config.Config, syncs api.SyncTrigger) error {
+	srv := &http.Server{
+		Addr:    ":5000",
+		Handler: api.NewRouter(cfg, syncs),
 	}
-	fmt.Fprintf(w, "XP budget is %d", xpBudget)
-}
-
-func getMonstersbyLevel(cfg config.Config, ctx context.Context, w http.ResponseWriter, r *http.Request) {
-	queries := writeMonsters.New(cfg.DBPool)
-	minStr := r.URL.Query().Get("min")
-	maxStr := r.URL.Query().Get("max")
-	//
-	monsters, err := queries.GetMonstersByLevelRange(ctx, writeMonsters.GetMonstersByLevelRangeParams{
-		Level:   utils.NewText(minStr),
-		Level_2: utils.NewText(maxStr),
-	})
-	if err != nil {
-
+	errs := make(chan error, 1)
+	go func() {
+		logger.Log.Info("listening on :5000")
+		errs <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
 	}
+	logger.Log.Info("Shutting down the API")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
 }
 
-func api(ctx context.Context, cfg config.Config) error {
-	http.HandleFunc("/calculatebudget", CalculatexpBudget)
-	http.HandleFunc("/MonstersInLevelRange", getMonstersbyLevel(cfg, ctx))
-	logger.Log.Info("listening on :5000")
-	http.ListenAndServe(":5000", nil)
-	return nil
+// runCommand handles the admin commands that run instead of the server.
+func runCommand(cfg config.Config, args []string) error {
+	switch args[0] {
+	case "rollback-sync":
+		// Restores the bestiary generation from before the last sync.
+		// Running it twice puts the newer generation back.
+		err := utils.RollbackSync(context.Background(), cfg.DBPool)
+		if err != nil {
+			return err
+		}
+		logger.Log.Info("Rolled back to the previous bestiary generation")
+		return nil
+	case "sync":
+		// Runs a sync now and waits for it to finish.
+		return utils.KickOffSync(cfg)
+	case "import":
+		// import [-source name] <archive.tar.gz|directory> [pack-pattern...]
+		// syncs a data source, pf2e by default, from local data, for
+		// machines without network access.
+		source := ""
+		if len(args) > 2 && args[1] == "-source" {
+			source = args[2]
+			args = append(args[:1], args[3:]...)
+		}
+		if len(args) < 2 {
+			return fmt.Errorf("usage: import [-source name] <archive.tar.gz|directory> [pack-pattern...]")
+		}
+		return utils.ImportSync(cfg, utils.ImportOptions{Path: args[1], Packs: args[2:], Source: source})
+	case "releases":
+		// Lists the upstream releases SYNC_RELEASE can be pinned to.
+		releases, err := utils.ListUpstreamReleases(context.Background(), cfg)
+		if err != nil {
+			return err
+		}
+		for _, release := range releases {
+			label := ""
+			if release.Prerelease {
+				label = " (prerelease)"
+			}
+			fmt.Printf("%s\t%s\t%s%s\n", release.Tag, release.PublishedAt.Format(time.DateOnly), release.Name, label)
+		}
+		return nil
+	case "extract":
+		// extract <archive.tar.gz> [directory] unpacks an archive for the
+		// local tools, into ./files by default. Syncs do not need it.
+		if len(args) < 2 || len(args) > 3 {
+			return fmt.Errorf("usage: extract <archive.tar.gz> [directory]")
+		}
+		dest := "files"
+		if len(args) == 3 {
+			dest = args[2]
+		}
+		return utils.ExtractArchive(args[1], dest)
+	}
+	return fmt.Errorf("unknown command %q, expected rollback-sync, sync, import, releases or extract", args[0])
 }
 
 func main() {
@@ -60,16 +107,43 @@ func main() {
 		slog.String("env", "development"),
 	)
 
-	//setup the sync cron for the db.
-	go utils.ManageDBSync(*cfg)
-	// //TODO Remove this else everytime the ap starts it'll rebuild the db.
-	// err := utils.KickOffSync(*cfg)
-	// if err != nil {
-	// 	logger.Log.Error(err.Error())
-	//}
+	if len(os.Args) > 1 {
+		err := runCommand(*cfg, os.Args[1:])
+		if err != nil {
+			logger.Log.Error("Command failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+	err := utils.EnsureLiveSchema(context.Background(), cfg.DBPool)
+	if err != nil {
+		logger.Log.Error("Unable to create or migrate the bestiary schema", "err", err)
+		os.Exit(1)
+	}
+	err = utils.EnsureOpsTables(context.Background(), cfg.DBPool)
+	if err != nil {
+		logger.Log.Error("Unable to create or migrate the operational tables", "err", err)
+		os.Exit(1)
+	}
 
-	err := api()
+	scheduler, err := utils.NewSyncScheduler(*cfg)
 	if err != nil {
-		logger.Log.Error("Unable to initialize APIS %w", err)
+		logger.Log.Error("Unable to schedule syncs", "err", err)
+		os.Exit(1)
+	}
+	scheduler.Start()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err = serve(ctx, *cfg, scheduler)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Log.Error("Unable to initialize APIS", "err", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := scheduler.Stop(shutdownCtx); err != nil {
+		logger.Log.Error("Sync did not stop before shutdown", "err", err)
 	}
+	cfg.DBPool.Close()
 }
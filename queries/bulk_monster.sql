@@ -0,0 +1,124 @@
+-- Bulk loaders for the child rows of a batch of monsters. Parent rows that
+-- other rows point at take their ids from AllocateIDs so children can be
+-- built before anything is sent.
+
+-- name: AllocateIDs :many
+-- AllocateIDs reserves id_count values from the serial sequence of a table.
+SELECT nextval(pg_get_serial_sequence(@table_name::text, 'id'))::int4 AS id
+FROM generate_series(1, @id_count::int4);
+
+-- name: CopyAttackDamageBlocks :copyfrom
+INSERT INTO attack_damage_blocks (attack_id, damage_roll, damage_type)
+VALUES ($1, $2, $3);
+
+-- name: CopyFocusSpellCasting :copyfrom
+INSERT INTO focus_spell_casting (id, monster_id, dc, mod, tradition, spellcasting_id, name, description, cast_level)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: CopyFocusSpellCastingSpells :copyfrom
+INSERT INTO focus_spell_casting_spells (focus_spell_casting_id, spell_id)
+VALUES ($1, $2);
+
+-- name: CopyInnateSpellCasting :copyfrom
+INSERT INTO innate_spell_casting (id, monster_id, dc, tradition, mod, spellcasting_id, description, name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: CopyInnateSpellUses :copyfrom
+INSERT INTO innate_spell_uses (innate_spell_casting_id, spell_id, level, uses)
+VALUES ($1, $2, $3, $4);
+
+-- name: CopyItemTraits :copyfrom
+INSERT INTO item_traits (item_id, trait)
+VALUES ($1, $2);
+
+-- name: CopyItems :copyfrom
+INSERT INTO items (id, monster_id, name, category, description, level, rarity, bulk, quantity, price_per, price_cp, price_sp, price_gp, price_pp)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+
+-- name: CopyMonsterActionTraits :copyfrom
+INSERT INTO monster_action_traits (monster_action_id, trait)
+VALUES ($1, $2);
+
+-- name: CopyMonsterActions :copyfrom
+INSERT INTO monster_actions (id, monster_id, action_type, name, text, actions, category, rarity, dc)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: CopyMonsterAttacks :copyfrom
+INSERT INTO monster_attacks (id, monster_id, attack_category, name, attack_type, to_hit_bonus, effects_custom_string, effects_values)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: CopyMonsterDamageModifiers :copyfrom
+INSERT INTO monster_damage_modifiers (id, monster_id, modifier_category, value, damage_type)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CopyMonsterImmunities :copyfrom
+INSERT INTO monster_immunities (monster_id, immunity)
+VALUES ($1, $2);
+
+-- name: CopyMonsterLanguages :copyfrom
+INSERT INTO monster_languages (monster_id, language)
+VALUES ($1, $2);
+
+-- name: CopyMonsterModifierDoubles :copyfrom
+INSERT INTO monster_modifier_doubles (modifier_id, double_value)
+VALUES ($1, $2);
+
+-- name: CopyMonsterModifierExceptions :copyfrom
+INSERT INTO monster_modifier_exceptions (modifier_id, exception)
+VALUES ($1, $2);
+
+-- name: CopyMonsterMovements :copyfrom
+INSERT INTO monster_movements (monster_id, movement_type, speed, notes)
+VALUES ($1, $2, $3, $4);
+
+-- name: CopyMonsterSenses :copyfrom
+INSERT INTO monster_senses (monster_id, name, range, acuity, detail)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CopyMonsterSkillSpecials :copyfrom
+INSERT INTO monster_skill_specials (skill_id, value, label, predicates)
+VALUES ($1, $2, $3, $4);
+
+-- name: CopyMonsterSkills :copyfrom
+INSERT INTO monster_skills (id, monster_id, name, value)
+VALUES ($1, $2, $3, $4);
+
+-- name: CopyMonsterTraits :copyfrom
+INSERT INTO monster_traits (monster_id, trait)
+VALUES ($1, $2);
+
+-- name: CopyPreparedSlots :copyfrom
+INSERT INTO prepared_slots (prepared_spell_casting_id, level, spell_id)
+VALUES ($1, $2, $3);
+
+-- name: CopyPreparedSpellCasting :copyfrom
+INSERT INTO prepared_spell_casting (id, monster_id, dc, tradition, mod, spellcasting_id, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: CopySpellAreas :copyfrom
+INSERT INTO spell_areas (spell_id, area_type, value, detail)
+VALUES ($1, $2, $3, $4);
+
+-- name: CopySpellDefenses :copyfrom
+INSERT INTO spell_defenses (spell_id, save, basic)
+VALUES ($1, $2, $3);
+
+-- name: CopySpellDurations :copyfrom
+INSERT INTO spell_durations (spell_id, sustained, duration)
+VALUES ($1, $2, $3);
+
+-- name: CopySpells :copyfrom
+INSERT INTO spells (id, monster_id, foundry_id, name, cast_level, spell_base_level, description, range, cast_time, cast_requirements, rarity, at_will, spell_casting_block_location_id, uses, ritual, targets)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
+
+-- name: CopySpontaneousSlots :copyfrom
+INSERT INTO spontaneous_slots (spontaneous_spell_casting_id, level, casts)
+VALUES ($1, $2, $3);
+
+-- name: CopySpontaneousSpellCasting :copyfrom
+INSERT INTO spontaneous_spell_casting (id, monster_id, dc, id_string, tradition, mod)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: CopySpontaneousSpellList :copyfrom
+INSERT INTO spontaneous_spell_list (spontaneous_spell_casting_id, spell_id)
+VALUES ($1, $2);
@@ -0,0 +1,35 @@
+-- name: GetDataVersion :one
+SELECT * FROM data_version
+WHERE source = $1;
+
+-- name: ListMonsterDataVersions :many
+-- Active monsters of a data source by the version they were last seen in.
+-- More than one row means some packs were synced from other data than the
+-- rest.
+SELECT data_version, count(*) AS monsters
+FROM monsters
+WHERE retired_at IS NULL
+  AND source = $1
+GROUP BY data_version
+ORDER BY monsters DESC, data_version;
+
+-- name: ReplaceDataVersion :exec
+WITH cleared AS (
+  DELETE FROM data_version
+  WHERE source = $1
+)
+INSERT INTO data_version (source, release, source_version, loaded_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: StampHazardsDataVersion :execrows
+UPDATE hazards
+SET data_version = @data_version
+WHERE last_seen_at = @seen_at
+  AND source = @source;
+
+-- name: StampMonstersDataVersion :execrows
+-- Records seen by the sync started at seen_at, parsed or unchanged.
+UPDATE monsters
+SET data_version = @data_version
+WHERE last_seen_at = @seen_at
+  AND source = @source;
@@ -0,0 +1,174 @@
+-- name: UpsertHazard :one
+INSERT INTO hazards (foundry_id,
+                     pack_path,
+                     content_hash,
+                     last_seen_at,
+                     name,
+                     level,
+                     is_complex,
+                     traits_rarity,
+                     stealth_value,
+                     stealth_detail,
+                     disable,
+                     ac_value,
+                     hardness,
+                     hp_value,
+                     hp_detail,
+                     saves_fort,
+                     saves_ref,
+                     saves_will,
+                     description,
+                     routine,
+                     reset,
+                     source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+ON CONFLICT (source, foundry_id, pack_path) DO UPDATE
+SET content_hash = EXCLUDED.content_hash,
+    last_seen_at = EXCLUDED.last_seen_at,
+    name = EXCLUDED.name,
+    level = EXCLUDED.level,
+    is_complex = EXCLUDED.is_complex,
+    traits_rarity = EXCLUDED.traits_rarity,
+    stealth_value = EXCLUDED.stealth_value,
+    stealth_detail = EXCLUDED.stealth_detail,
+    disable = EXCLUDED.disable,
+    ac_value = EXCLUDED.ac_value,
+    hardness = EXCLUDED.hardness,
+    hp_value = EXCLUDED.hp_value,
+    hp_detail = EXCLUDED.hp_detail,
+    saves_fort = EXCLUDED.saves_fort,
+    saves_ref = EXCLUDED.saves_ref,
+    saves_will = EXCLUDED.saves_will,
+    description = EXCLUDED.description,
+    routine = EXCLUDED.routine,
+    reset = EXCLUDED.reset,
+    retired_at = NULL
+RETURNING id;
+
+-- name: DeleteHazardChildren :exec
+WITH d_traits AS (DELETE FROM hazard_traits WHERE hazard_id = $1),
+     d_immunities AS (DELETE FROM hazard_immunities WHERE hazard_id = $1),
+     d_actions AS (DELETE FROM hazard_actions WHERE hazard_id = $1)
+DELETE FROM hazard_attacks WHERE hazard_id = $1;
+
+-- name: GetHazardSyncState :one
+SELECT id, content_hash, retired_at
+FROM hazards
+WHERE foundry_id = $1 AND pack_path = $2 AND source = $3;
+
+-- name: MarkHazardSeen :exec
+UPDATE hazards SET last_seen_at = $2 WHERE id = $1;
+
+-- name: RetireUnseenHazards :execrows
+UPDATE hazards
+SET retired_at = now()
+WHERE retired_at IS NULL
+  AND (last_seen_at IS NULL OR last_seen_at < $1)
+  AND source = $2;
+
+-- name: InsertHazardTraits :exec
+INSERT INTO hazard_traits (hazard_id, trait)
+VALUES ($1, $2);
+
+-- name: InsertHazardImmunities :exec
+INSERT INTO hazard_immunities (hazard_id, immunity)
+VALUES ($1, $2);
+
+-- name: InsertHazardAction :exec
+INSERT INTO hazard_actions (hazard_id, action_type, name, text, actions)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: InsertHazardAttack :one
+INSERT INTO hazard_attacks (hazard_id, attack_category, name, attack_type, to_hit_bonus)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: InsertHazardAttackDamageBlock :exec
+INSERT INTO hazard_attack_damage_blocks (attack_id, damage_roll, damage_type)
+VALUES ($1, $2, $3);
+
+-- name: GetFullHazardByID :one
+SELECT row_to_json(hazard_data)
+FROM (
+  SELECT h.*,
+    (
+      SELECT json_agg(ht.trait)
+      FROM hazard_traits ht
+      WHERE ht.hazard_id = h.id
+    ) AS traits,
+
+    (
+      SELECT json_agg(hi.immunity)
+      FROM hazard_immunities hi
+      WHERE hi.hazard_id = h.id
+    ) AS immunities,
+
+    (
+      SELECT json_agg(ha)
+      FROM hazard_actions ha
+      WHERE ha.hazard_id = h.id
+    ) AS actions,
+
+    (
+      SELECT json_agg(
+        json_build_object(
+          'id', hat.id,
+          'attack_category', hat.attack_category,
+          'name', hat.name,
+          'attack_type', hat.attack_type,
+          'to_hit_bonus', hat.to_hit_bonus,
+          'damage_blocks', (
+            SELECT json_agg(hdb)
+            FROM hazard_attack_damage_blocks hdb
+            WHERE hdb.attack_id = hat.id
+          )
+        )
+      )
+      FROM hazard_attacks hat
+      WHERE hat.hazard_id = h.id
+    ) AS attacks
+  FROM hazards h
+  WHERE h.id = $1 AND h.retired_at IS NULL
+) hazard_data;
+
+-- name: GetHazardLevelsByIDs :many
+SELECT h.id, h.name, h.level, h.is_complex
+FROM hazards h
+WHERE h.id = ANY(sqlc.arg('ids')::integer[]) AND h.retired_at IS NULL;
+
+-- name: SearchHazards :many
+SELECT h.id,
+       h.name,
+       h.level,
+       h.is_complex,
+       h.traits_rarity,
+       h.stealth_value,
+       h.source,
+       (
+         SELECT json_agg(ht.trait)
+         FROM hazard_traits ht
+         WHERE ht.hazard_id = h.id
+       ) AS traits,
+       COUNT(*) OVER () AS total_count
+FROM hazards h
+WHERE h.retired_at IS NULL
+  AND (sqlc.narg('name')::text IS NULL OR h.name ILIKE '%' || sqlc.narg('name')::text || '%')
+  AND (sqlc.narg('min_level')::integer IS NULL OR h.level >= sqlc.narg('min_level')::integer)
+  AND (sqlc.narg('max_level')::integer IS NULL OR h.level <= sqlc.narg('max_level')::integer)
+  AND (sqlc.narg('is_complex')::boolean IS NULL OR h.is_complex = sqlc.narg('is_complex')::boolean)
+  AND (sqlc.narg('source')::text IS NULL OR h.source = sqlc.narg('source')::text)
+ORDER BY h.level ASC, h.name ASC, h.id ASC
+LIMIT sqlc.arg('page_limit')::integer
+OFFSET sqlc.arg('page_offset')::integer;
+
+-- name: CountHazards :one
+-- CountHazards counts every match of SearchHazards' filters, for a page past
+-- the last match.
+SELECT COUNT(*)
+FROM hazards h
+WHERE h.retired_at IS NULL
+  AND (sqlc.narg('name')::text IS NULL OR h.name ILIKE '%' || sqlc.narg('name')::text || '%')
+  AND (sqlc.narg('min_level')::integer IS NULL OR h.level >= sqlc.narg('min_level')::integer)
+  AND (sqlc.narg('max_level')::integer IS NULL OR h.level <= sqlc.narg('max_level')::integer)
+  AND (sqlc.narg('is_complex')::boolean IS NULL OR h.is_complex = sqlc.narg('is_complex')::boolean)
+  AND (sqlc.narg('source')::text IS NULL OR h.source = sqlc.narg('source')::text);
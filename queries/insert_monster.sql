@@ -1,31 +1,102 @@
--- name: InsertMonster :one
-INSERT INTO monsters (name, 
-                        level, 
-                        focus_points, 
-                        traits_rarity, 
-                        traits_size, 
-                        attr_str, 
-                        attr_dex, 
-                        attr_con, 
-                        attr_wis,
-                        attr_int, 
-                        attr_cha, 
-                        saves_fort, 
-                        saves_fort_detail,
-                        saves_ref, 
-                        saves_ref_detail, 
-                        saves_will, 
-                        saves_will_detail,
-                        saves_exception, 
-                        ac_value, 
-                        ac_detail,
-                        hp_value, 
-                        hp_detail, 
-                        perception_mod, 
-                        perception_detail)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+-- name: UpsertMonster :one
+-- Monsters are keyed by their Foundry _id within a pack of a data source so a
+-- re-sync updates the existing row instead of adding a copy.
+INSERT INTO monsters (foundry_id,
+                      pack_path,
+                      content_hash,
+                      last_seen_at,
+                      name,
+                      level,
+                      focus_points,
+                      traits_rarity,
+                      traits_size,
+                      attr_str,
+                      attr_dex,
+                      attr_con,
+                      attr_wis,
+                      attr_int,
+                      attr_cha,
+                      saves_fort,
+                      saves_fort_detail,
+                      saves_ref,
+                      saves_ref_detail,
+                      saves_will,
+                      saves_will_detail,
+                      saves_exception,
+                      ac_value,
+                      ac_detail,
+                      hp_value,
+                      hp_detail,
+                      perception_mod,
+                      perception_detail,
+                      source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)
+ON CONFLICT (source, foundry_id, pack_path) DO UPDATE
+SET content_hash = EXCLUDED.content_hash,
+    last_seen_at = EXCLUDED.last_seen_at,
+    name = EXCLUDED.name,
+    level = EXCLUDED.level,
+    focus_points = EXCLUDED.focus_points,
+    traits_rarity = EXCLUDED.traits_rarity,
+    traits_size = EXCLUDED.traits_size,
+    attr_str = EXCLUDED.attr_str,
+    attr_dex = EXCLUDED.attr_dex,
+    attr_con = EXCLUDED.attr_con,
+    attr_wis = EXCLUDED.attr_wis,
+    attr_int = EXCLUDED.attr_int,
+    attr_cha = EXCLUDED.attr_cha,
+    saves_fort = EXCLUDED.saves_fort,
+    saves_fort_detail = EXCLUDED.saves_fort_detail,
+    saves_ref = EXCLUDED.saves_ref,
+    saves_ref_detail = EXCLUDED.saves_ref_detail,
+    saves_will = EXCLUDED.saves_will,
+    saves_will_detail = EXCLUDED.saves_will_detail,
+    saves_exception = EXCLUDED.saves_exception,
+    ac_value = EXCLUDED.ac_value,
+    ac_detail = EXCLUDED.ac_detail,
+    hp_value = EXCLUDED.hp_value,
+    hp_detail = EXCLUDED.hp_detail,
+    perception_mod = EXCLUDED.perception_mod,
+    perception_detail = EXCLUDED.perception_detail,
+    retired_at = NULL
 RETURNING id;
 
+-- name: DeleteMonsterChildren :exec
+-- Clears every row hanging off a monster before it is rewritten. Grandchild
+-- rows go with their parents through ON DELETE CASCADE.
+WITH d_monster_traits AS (DELETE FROM monster_traits WHERE monster_id = $1),
+     d_monster_immunities AS (DELETE FROM monster_immunities WHERE monster_id = $1),
+     d_monster_damage_modifiers AS (DELETE FROM monster_damage_modifiers WHERE monster_id = $1),
+     d_monster_languages AS (DELETE FROM monster_languages WHERE monster_id = $1),
+     d_monster_senses AS (DELETE FROM monster_senses WHERE monster_id = $1),
+     d_monster_skills AS (DELETE FROM monster_skills WHERE monster_id = $1),
+     d_monster_movements AS (DELETE FROM monster_movements WHERE monster_id = $1),
+     d_monster_actions AS (DELETE FROM monster_actions WHERE monster_id = $1),
+     d_monster_attacks AS (DELETE FROM monster_attacks WHERE monster_id = $1),
+     d_focus_spell_casting AS (DELETE FROM focus_spell_casting WHERE monster_id = $1),
+     d_innate_spell_casting AS (DELETE FROM innate_spell_casting WHERE monster_id = $1),
+     d_prepared_spell_casting AS (DELETE FROM prepared_spell_casting WHERE monster_id = $1),
+     d_spontaneous_spell_casting AS (DELETE FROM spontaneous_spell_casting WHERE monster_id = $1),
+     d_spells AS (DELETE FROM spells WHERE monster_id = $1)
+DELETE FROM items WHERE monster_id = $1;
+
+-- name: GetMonsterSyncState :one
+SELECT id, content_hash, retired_at
+FROM monsters
+WHERE foundry_id = $1 AND pack_path = $2 AND source = $3;
+
+-- name: MarkMonsterSeen :exec
+UPDATE monsters SET last_seen_at = $2 WHERE id = $1;
+
+-- name: RetireUnseenMonsters :execrows
+-- Monsters that were not seen by a sync started at $1 have been removed
+-- upstream. They stay in the table so saved encounters still resolve.
+UPDATE monsters
+SET retired_at = now()
+WHERE retired_at IS NULL
+  AND (last_seen_at IS NULL OR last_seen_at < $1)
+  AND source = $2;
+
 -- name: InsertMonsterTraits :exec
 INSERT INTO monster_traits (monster_id, trait)
 VALUES ($1, $2);
@@ -87,9 +158,9 @@ INSERT INTO attack_damage_blocks (attack_id, damage_roll, damage_type)
 VALUES ($1, $2, $3);
 
 -- name: InsertSpell :one
-INSERT INTO spells (name, cast_level, spell_base_level, description, range, cast_time, cast_requirements, rarity, at_will, spell_casting_block_location_id, uses, ritual, targets)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-RETURNING id; 
+INSERT INTO spells (id, monster_id, foundry_id, name, cast_level, spell_base_level, description, range, cast_time, cast_requirements, rarity, at_will, spell_casting_block_location_id, uses, ritual, targets)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+RETURNING id;
 
 -- name: InsertSpellArea :exec
 INSERT INTO spell_areas (spell_id, area_type, value, detail)
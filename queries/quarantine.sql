@@ -0,0 +1,15 @@
+-- name: CopyQuarantinedItems :copyfrom
+INSERT INTO quarantined_items (run_id, path, item_name, reason, raw)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetQuarantinedItem :one
+SELECT * FROM quarantined_items
+WHERE id = $1;
+
+-- name: ListQuarantinedItems :many
+SELECT id, run_id, path, item_name, reason
+FROM quarantined_items
+WHERE (sqlc.narg('run_id')::integer IS NULL OR run_id = sqlc.narg('run_id')::integer)
+  AND (sqlc.narg('reason')::text IS NULL OR reason ILIKE '%' || sqlc.narg('reason')::text || '%')
+ORDER BY id DESC
+LIMIT @page_limit OFFSET @page_offset;
@@ -2,6 +2,12 @@
 SELECT row_to_json(monster_data)
 FROM (
   SELECT m.*,
+    (
+      SELECT json_agg(mt.trait)
+      FROM monster_traits mt
+      WHERE mt.monster_id = m.id
+    ) AS traits,
+
     (
       SELECT json_agg(mi)
       FROM monster_immunities mi
@@ -245,7 +251,7 @@ FROM (
     ) AS items
 
   FROM monsters m
-  WHERE m.id = $1
+  WHERE m.id = $1 AND m.retired_at IS NULL
 ) monster_data;
 
 -- name: SearchMonsterByName :many
@@ -253,14 +259,14 @@ SELECT row_to_json(monster_data)
 FROM (
   SELECT m.*
   FROM monsters m
-  WHERE m.name ILIKE '%' || $1 || '%'
+  WHERE m.name ILIKE '%' || $1 || '%' AND m.retired_at IS NULL
 ) monster_data;
 
 -- name: GetMonstersByTrait :many
 SELECT m.*
 FROM monsters m
 JOIN monster_traits mt ON m.id = mt.monster_id
-WHERE mt.trait = $1;
+WHERE mt.trait = $1 AND m.retired_at IS NULL;
 
 
 -- name: GetMonstersByLevelRange :many
@@ -509,6 +515,11 @@ FROM (
       WHERE i.monster_id = m.id
     ) AS items
   FROM monsters m
-  WHERE m.level BETWEEN $1 AND $2
+  WHERE m.level BETWEEN $1 AND $2 AND m.retired_at IS NULL
 ) monster_data;
 
+
+-- name: GetMonsterLevelsByIDs :many
+SELECT m.id, m.name, m.level
+FROM monsters m
+WHERE m.id = ANY(sqlc.arg('ids')::integer[]) AND m.retired_at IS NULL;
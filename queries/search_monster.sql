@@ -0,0 +1,126 @@
+-- name: SearchMonsters :many
+SELECT m.id,
+       m.name,
+       m.level,
+       m.traits_rarity,
+       m.traits_size,
+       m.hp_value,
+       m.ac_value,
+       m.source,
+       (
+         SELECT json_agg(mt.trait)
+         FROM monster_traits mt
+         WHERE mt.monster_id = m.id
+       ) AS traits,
+       COUNT(*) OVER () AS total_count
+FROM monsters m
+WHERE m.retired_at IS NULL
+  AND (sqlc.narg('name')::text IS NULL OR m.name ILIKE '%' || sqlc.narg('name')::text || '%')
+  AND (sqlc.narg('min_level')::integer IS NULL OR m.level >= sqlc.narg('min_level')::integer)
+  AND (sqlc.narg('max_level')::integer IS NULL OR m.level <= sqlc.narg('max_level')::integer)
+  AND (sqlc.narg('traits_any')::text[] IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_traits mt
+        WHERE mt.monster_id = m.id
+          AND mt.trait = ANY(sqlc.narg('traits_any')::text[])
+      ))
+  AND (sqlc.narg('traits_all')::text[] IS NULL OR (
+        SELECT COUNT(DISTINCT mt.trait)
+        FROM monster_traits mt
+        WHERE mt.monster_id = m.id
+          AND mt.trait = ANY(sqlc.narg('traits_all')::text[])
+      ) = cardinality(sqlc.narg('traits_all')::text[]))
+  AND (sqlc.narg('rarity')::text IS NULL OR m.traits_rarity = sqlc.narg('rarity')::text)
+  AND (sqlc.narg('size')::text IS NULL OR m.traits_size = sqlc.narg('size')::text)
+  AND (sqlc.narg('immunity')::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_immunities mi
+        WHERE mi.monster_id = m.id
+          AND mi.immunity = sqlc.narg('immunity')::text
+      ))
+  AND (sqlc.narg('weakness')::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_damage_modifiers md
+        WHERE md.monster_id = m.id
+          AND md.modifier_category = 'weakness'
+          AND md.damage_type = sqlc.narg('weakness')::text
+      ))
+  AND (sqlc.narg('movement')::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_movements mm
+        WHERE mm.monster_id = m.id
+          AND mm.movement_type = sqlc.narg('movement')::text
+      ))
+  AND (sqlc.narg('source')::text IS NULL OR m.source = sqlc.narg('source')::text)
+ORDER BY
+  CASE WHEN sqlc.arg('sort_by')::text = 'level' AND NOT sqlc.arg('sort_desc')::boolean THEN m.level END ASC,
+  CASE WHEN sqlc.arg('sort_by')::text = 'level' AND sqlc.arg('sort_desc')::boolean THEN m.level END DESC,
+  CASE WHEN sqlc.arg('sort_by')::text = 'hp' AND NOT sqlc.arg('sort_desc')::boolean THEN m.hp_value END ASC,
+  CASE WHEN sqlc.arg('sort_by')::text = 'hp' AND sqlc.arg('sort_desc')::boolean THEN m.hp_value END DESC,
+  CASE WHEN sqlc.arg('sort_by')::text = 'name' AND NOT sqlc.arg('sort_desc')::boolean THEN m.name END ASC,
+  CASE WHEN sqlc.arg('sort_by')::text = 'name' AND sqlc.arg('sort_desc')::boolean THEN m.name END DESC,
+  m.name ASC,
+  m.id ASC
+LIMIT sqlc.arg('page_limit')::integer
+OFFSET sqlc.arg('page_offset')::integer;
+
+-- name: CountMonsters :one
+-- CountMonsters counts every match of SearchMonsters' filters. The search
+-- returns the total with each row, so this is only needed for a page past the
+-- last match.
+SELECT COUNT(*)
+FROM monsters m
+WHERE m.retired_at IS NULL
+  AND (sqlc.narg('name')::text IS NULL OR m.name ILIKE '%' || sqlc.narg('name')::text || '%')
+  AND (sqlc.narg('min_level')::integer IS NULL OR m.level >= sqlc.narg('min_level')::integer)
+  AND (sqlc.narg('max_level')::integer IS NULL OR m.level <= sqlc.narg('max_level')::integer)
+  AND (sqlc.narg('traits_any')::text[] IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_traits mt
+        WHERE mt.monster_id = m.id
+          AND mt.trait = ANY(sqlc.narg('traits_any')::text[])
+      ))
+  AND (sqlc.narg('traits_all')::text[] IS NULL OR (
+        SELECT COUNT(DISTINCT mt.trait)
+        FROM monster_traits mt
+        WHERE mt.monster_id = m.id
+          AND mt.trait = ANY(sqlc.narg('traits_all')::text[])
+      ) = cardinality(sqlc.narg('traits_all')::text[]))
+  AND (sqlc.narg('rarity')::text IS NULL OR m.traits_rarity = sqlc.narg('rarity')::text)
+  AND (sqlc.narg('size')::text IS NULL OR m.traits_size = sqlc.narg('size')::text)
+  AND (sqlc.narg('immunity')::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_immunities mi
+        WHERE mi.monster_id = m.id
+          AND mi.immunity = sqlc.narg('immunity')::text
+      ))
+  AND (sqlc.narg('weakness')::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_damage_modifiers md
+        WHERE md.monster_id = m.id
+          AND md.modifier_category = 'weakness'
+          AND md.damage_type = sqlc.narg('weakness')::text
+      ))
+  AND (sqlc.narg('movement')::text IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_movements mm
+        WHERE mm.monster_id = m.id
+          AND mm.movement_type = sqlc.narg('movement')::text
+      ))
+  AND (sqlc.narg('source')::text IS NULL OR m.source = sqlc.narg('source')::text);
+
+-- name: GetEncounterCandidates :many
+SELECT m.id, m.name, m.level
+FROM monsters m
+WHERE m.retired_at IS NULL
+  AND m.level BETWEEN sqlc.arg('min_level')::integer AND sqlc.arg('max_level')::integer
+  AND (sqlc.narg('traits_any')::text[] IS NULL OR EXISTS (
+        SELECT 1
+        FROM monster_traits mt
+        WHERE mt.monster_id = m.id
+          AND mt.trait = ANY(sqlc.narg('traits_any')::text[])
+      ))
+  AND (sqlc.narg('rarity')::text IS NULL OR m.traits_rarity = sqlc.narg('rarity')::text)
+  AND (sqlc.narg('size')::text IS NULL OR m.traits_size = sqlc.narg('size')::text)
+  AND (sqlc.narg('source')::text IS NULL OR m.source = sqlc.narg('source')::text)
+ORDER BY m.id;
@@ -0,0 +1,44 @@
+-- name: ListSourceFiles :many
+SELECT * FROM source_files
+WHERE source = $1;
+
+-- name: UpsertSourceFiles :exec
+INSERT INTO source_files (source, path, foundry_id, pack_path, content_hash, last_seen_at)
+SELECT @source::text,
+       unnest(@paths::text[]),
+       unnest(@foundry_ids::text[]),
+       unnest(@pack_paths::text[]),
+       unnest(@content_hashes::text[]),
+       @seen_at::timestamptz
+ON CONFLICT (source, path) DO UPDATE
+SET foundry_id = EXCLUDED.foundry_id,
+    pack_path = EXCLUDED.pack_path,
+    content_hash = EXCLUDED.content_hash,
+    last_seen_at = EXCLUDED.last_seen_at;
+
+-- name: MarkMonstersSeenFromSourceFiles :execrows
+-- Monsters loaded from a file seen by the sync started at $1, including the
+-- unchanged files it did not parse.
+UPDATE monsters m
+SET last_seen_at = $1
+FROM source_files f
+WHERE f.last_seen_at = $1
+  AND m.source = f.source
+  AND m.foundry_id = f.foundry_id
+  AND m.pack_path = f.pack_path
+  AND m.retired_at IS NULL;
+
+-- name: MarkHazardsSeenFromSourceFiles :execrows
+UPDATE hazards h
+SET last_seen_at = $1
+FROM source_files f
+WHERE f.last_seen_at = $1
+  AND h.source = f.source
+  AND h.foundry_id = f.foundry_id
+  AND h.pack_path = f.pack_path
+  AND h.retired_at IS NULL;
+
+-- name: DeleteUnseenSourceFiles :execrows
+DELETE FROM source_files
+WHERE last_seen_at < $1
+  AND source = $2;
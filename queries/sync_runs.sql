@@ -0,0 +1,45 @@
+-- name: CopySyncFailures :copyfrom
+INSERT INTO sync_failures (run_id, path, error)
+VALUES ($1, $2, $3);
+
+-- name: CreateSyncRun :one
+INSERT INTO sync_runs (started_at, source)
+VALUES ($1, $2)
+RETURNING id;
+
+-- name: FinishSyncRun :exec
+UPDATE sync_runs
+SET finished_at = @finished_at,
+    status = @status,
+    source_version = @source_version,
+    release = @release,
+    archive_checksum = @archive_checksum,
+    pack_filter = @pack_filter,
+    files_seen = @files_seen,
+    files_parsed = @files_parsed,
+    records_inserted = @records_inserted,
+    records_updated = @records_updated,
+    files_skipped = @files_skipped,
+    files_failed = @files_failed,
+    error = @error
+WHERE id = @id;
+
+-- name: GetSyncRun :one
+SELECT * FROM sync_runs
+WHERE id = $1;
+
+-- name: GetLastSucceededSyncRun :one
+SELECT * FROM sync_runs
+WHERE status = 'succeeded' AND pack_filter IS NULL AND source = $1
+ORDER BY started_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListSyncFailures :many
+SELECT * FROM sync_failures
+WHERE run_id = $1
+ORDER BY id;
+
+-- name: ListSyncRuns :many
+SELECT * FROM sync_runs
+ORDER BY started_at DESC, id DESC
+LIMIT @page_limit OFFSET @page_offset;
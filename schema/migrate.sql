@@ -0,0 +1,54 @@
+-- Brings a live schema created by an earlier release up to date with
+-- schema.sql. A sync replaces the live tables with a fresh copy of schema.sql,
+-- but the API reads them before the first sync and after one that is skipped.
+-- Every statement must be safe to run more than once.
+
+CREATE TABLE IF NOT EXISTS source_files (
+    path TEXT PRIMARY KEY,
+    foundry_id VARCHAR(50) NOT NULL,
+    pack_path TEXT NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE monsters ADD COLUMN IF NOT EXISTS data_version TEXT;
+ALTER TABLE hazards ADD COLUMN IF NOT EXISTS data_version TEXT;
+
+CREATE TABLE IF NOT EXISTS data_version (
+    release TEXT,
+    source_version TEXT NOT NULL,
+    loaded_at TIMESTAMPTZ NOT NULL
+);
+
+-- Rows loaded before data sources were registered all came from pf2e.
+ALTER TABLE monsters ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT 'pf2e';
+ALTER TABLE hazards ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT 'pf2e';
+ALTER TABLE source_files ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT 'pf2e';
+ALTER TABLE data_version ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT 'pf2e';
+
+-- Foundry ids and pack paths are only unique within one data source, and
+-- source_files and data_version gain the source in their keys.
+ALTER TABLE monsters DROP CONSTRAINT IF EXISTS monsters_foundry_id_pack_path_key;
+ALTER TABLE hazards DROP CONSTRAINT IF EXISTS hazards_foundry_id_pack_path_key;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+            WHERE conrelid = 'monsters'::regclass AND conname = 'monsters_source_foundry_id_pack_path_key') THEN
+        ALTER TABLE monsters ADD CONSTRAINT monsters_source_foundry_id_pack_path_key UNIQUE (source, foundry_id, pack_path);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+            WHERE conrelid = 'hazards'::regclass AND conname = 'hazards_source_foundry_id_pack_path_key') THEN
+        ALTER TABLE hazards ADD CONSTRAINT hazards_source_foundry_id_pack_path_key UNIQUE (source, foundry_id, pack_path);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_index i
+            JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY (i.indkey)
+            WHERE i.indrelid = 'source_files'::regclass AND i.indisprimary AND a.attname = 'source') THEN
+        ALTER TABLE source_files DROP CONSTRAINT IF EXISTS source_files_pkey;
+        ALTER TABLE source_files ADD PRIMARY KEY (source, path);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+            WHERE conrelid = 'data_version'::regclass AND contype = 'p') THEN
+        ALTER TABLE data_version ADD PRIMARY KEY (source);
+    END IF;
+END
+$$;
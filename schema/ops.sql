@@ -0,0 +1,58 @@
+-- Operational tables. These live in the public schema, outside the bestiary
+-- generations that a sync swaps in and out, so their history survives swaps
+-- and rollbacks.
+
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id SERIAL PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed', 'rejected', 'skipped')),
+    source_version TEXT,
+    -- Release tag the sync was pinned to or resolved as latest.
+    release TEXT,
+    archive_checksum VARCHAR(64),
+    -- Pack patterns an offline import was restricted to; NULL for a full sync.
+    pack_filter TEXT[],
+    files_seen INTEGER NOT NULL DEFAULT 0,
+    files_parsed INTEGER NOT NULL DEFAULT 0,
+    records_inserted INTEGER NOT NULL DEFAULT 0,
+    records_updated INTEGER NOT NULL DEFAULT 0,
+    files_skipped INTEGER NOT NULL DEFAULT 0,
+    files_failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    -- Registered data source the run loaded.
+    source VARCHAR(50) NOT NULL DEFAULT 'pf2e'
+);
+
+-- CREATE TABLE IF NOT EXISTS leaves a sync_runs table made by an earlier
+-- release as it was, so columns and states added since are migrated here.
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS archive_checksum VARCHAR(64);
+ALTER TABLE sync_runs DROP CONSTRAINT IF EXISTS sync_runs_status_check;
+ALTER TABLE sync_runs ADD CONSTRAINT sync_runs_status_check
+    CHECK (status IN ('running', 'succeeded', 'failed', 'rejected', 'skipped'));
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS pack_filter TEXT[];
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS release TEXT;
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT 'pf2e';
+
+CREATE TABLE IF NOT EXISTS sync_failures (
+    id SERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES sync_runs(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    error TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sync_failures_run_id_idx ON sync_failures (run_id);
+
+-- Documents, and items embedded in them, that the parser could not classify.
+-- item_name is NULL when the whole document was quarantined. raw is kept as
+-- text because some entries are quarantined for not being valid JSON.
+CREATE TABLE IF NOT EXISTS quarantined_items (
+    id SERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES sync_runs(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    item_name TEXT,
+    reason TEXT NOT NULL,
+    raw TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS quarantined_items_run_id_idx ON quarantined_items (run_id);
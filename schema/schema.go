@@ -0,0 +1,37 @@
+// Package schema embeds the bestiary DDL so the application can build a
+// fresh copy of the tables, e.g. a staging schema for a sync.
+package schema
+
+import (
+	_ "embed"
+	"regexp"
+)
+
+// DDL creates every bestiary table in the current search_path.
+//
+//go:embed schema.sql
+var DDL string
+
+// OpsDDL creates the operational tables, such as the sync history, that sit
+// outside the bestiary generations. It is safe to run more than once.
+//
+//go:embed ops.sql
+var OpsDDL string
+
+// MigrateDDL updates a live schema created from an earlier DDL in the current
+// search_path. It is safe to run more than once.
+//
+//go:embed migrate.sql
+var MigrateDDL string
+
+var createTablePattern = regexp.MustCompile(`(?m)^CREATE TABLE (\w+)`)
+
+// Tables lists the bestiary tables in creation order, which is also an order
+// that satisfies every foreign key.
+func Tables() []string {
+	var tables []string
+	for _, match := range createTablePattern.FindAllStringSubmatch(DDL, -1) {
+		tables = append(tables, match[1])
+	}
+	return tables
+}
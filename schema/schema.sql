@@ -1,34 +1,46 @@
 CREATE TABLE monsters (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
-    level VARCHAR(50),
+    level INTEGER,
     focus_points INTEGER,
     -- Traits (one-to-one)
     traits_rarity VARCHAR(50),
     traits_size VARCHAR(50),
     -- Attributes (one-to-one)
-    attr_str VARCHAR(10),
-    attr_dex VARCHAR(10),
-    attr_con VARCHAR(10),
-    attr_wis VARCHAR(10),
-    attr_int VARCHAR(10),
-    attr_cha VARCHAR(10),
+    attr_str INTEGER,
+    attr_dex INTEGER,
+    attr_con INTEGER,
+    attr_wis INTEGER,
+    attr_int INTEGER,
+    attr_cha INTEGER,
     -- Saves (one-to-one)
-    saves_fort VARCHAR(20),
+    saves_fort INTEGER,
     saves_fort_detail TEXT,
-    saves_ref VARCHAR(20),
+    saves_ref INTEGER,
     saves_ref_detail TEXT,
-    saves_will VARCHAR(20),
+    saves_will INTEGER,
     saves_will_detail TEXT,
     saves_exception TEXT,
     -- AC and HP
-    ac_value VARCHAR(50),
+    ac_value INTEGER,
     ac_detail TEXT,
     hp_detail TEXT,
     hp_value INTEGER,
     -- Perception
     perception_mod VARCHAR(50),
-    perception_detail TEXT
+    perception_detail TEXT,
+    -- Sync bookkeeping: the Foundry document this row was loaded from
+    foundry_id VARCHAR(50),
+    pack_path TEXT,
+    content_hash VARCHAR(64),
+    last_seen_at TIMESTAMPTZ,
+    retired_at TIMESTAMPTZ,
+    -- Upstream release, or commit when the sync followed a branch, the row
+    -- was last seen in
+    data_version TEXT,
+    -- Registered data source the row was loaded from, e.g. pf2e or sf2e
+    source VARCHAR(50) NOT NULL DEFAULT 'pf2e',
+    UNIQUE (source, foundry_id, pack_path)
 );
 
 CREATE TABLE monster_traits (
@@ -135,7 +147,12 @@ CREATE TABLE attack_damage_blocks (
 
 
 CREATE TABLE spells (
+    -- Generated for every write. A Foundry item id is only unique within the
+    -- actor carrying it, so each monster gets its own rows, kept in
+    -- foundry_id, and they are rewritten with the monster.
     id VARCHAR(50) PRIMARY KEY,
+    monster_id INTEGER REFERENCES monsters(id) ON DELETE CASCADE,
+    foundry_id VARCHAR(50),
     name VARCHAR(100),
     cast_level VARCHAR(50),
     spell_base_level VARCHAR(50),
@@ -201,7 +218,7 @@ CREATE TABLE focus_spell_casting (
 CREATE TABLE focus_spell_casting_spells (
     id SERIAL PRIMARY KEY,
     focus_spell_casting_id INTEGER REFERENCES focus_spell_casting(id) ON DELETE CASCADE,
-    spell_id VARCHAR(50) REFERENCES spells(id)
+    spell_id VARCHAR(50) REFERENCES spells(id) ON DELETE CASCADE
 );
 CREATE TABLE innate_spell_casting (
     id SERIAL PRIMARY KEY,
@@ -217,7 +234,7 @@ CREATE TABLE innate_spell_casting (
 CREATE TABLE innate_spell_uses (
     id SERIAL PRIMARY KEY,
     innate_spell_casting_id INTEGER REFERENCES innate_spell_casting(id) ON DELETE CASCADE,
-    spell_id VARCHAR(50) REFERENCES spells(id),
+    spell_id VARCHAR(50) REFERENCES spells(id) ON DELETE CASCADE,
     level INTEGER,
     uses VARCHAR(50)
 );
@@ -235,7 +252,7 @@ CREATE TABLE prepared_slots (
     id SERIAL PRIMARY KEY,
     prepared_spell_casting_id INTEGER REFERENCES prepared_spell_casting(id) ON DELETE CASCADE,
     level VARCHAR(50),
-    spell_id VARCHAR(50) REFERENCES spells(id)
+    spell_id VARCHAR(50) REFERENCES spells(id) ON DELETE CASCADE
 );
 CREATE TABLE spontaneous_spell_casting (
     id SERIAL PRIMARY KEY,
@@ -256,7 +273,7 @@ CREATE TABLE spontaneous_slots (
 CREATE TABLE spontaneous_spell_list (
     id SERIAL PRIMARY KEY,
     spontaneous_spell_casting_id INTEGER REFERENCES spontaneous_spell_casting(id) ON DELETE CASCADE,
-    spell_id VARCHAR(50) REFERENCES spells(id)
+    spell_id VARCHAR(50) REFERENCES spells(id) ON DELETE CASCADE
 );
 CREATE TABLE items (
     id VARCHAR(50) PRIMARY KEY,
@@ -284,3 +301,101 @@ CREATE TABLE item_traits (
     item_id VARCHAR(50) REFERENCES items(id) ON DELETE CASCADE,
     trait VARCHAR(50)
 );
+
+CREATE TABLE hazards (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    level INTEGER,
+    is_complex BOOLEAN NOT NULL DEFAULT FALSE,
+    traits_rarity VARCHAR(50),
+    -- Detection and disabling
+    stealth_value INTEGER,
+    stealth_detail TEXT,
+    disable TEXT,
+    -- Defenses; hazards without health leave these NULL
+    ac_value INTEGER,
+    hardness INTEGER,
+    hp_value INTEGER,
+    hp_detail TEXT,
+    saves_fort INTEGER,
+    saves_ref INTEGER,
+    saves_will INTEGER,
+    description TEXT,
+    -- Complex hazards only
+    routine TEXT,
+    reset TEXT,
+    -- Sync bookkeeping: the Foundry document this row was loaded from
+    foundry_id VARCHAR(50),
+    pack_path TEXT,
+    content_hash VARCHAR(64),
+    last_seen_at TIMESTAMPTZ,
+    retired_at TIMESTAMPTZ,
+    -- Upstream release, or commit when the sync followed a branch, the row
+    -- was last seen in
+    data_version TEXT,
+    -- Registered data source the row was loaded from, e.g. pf2e or sf2e
+    source VARCHAR(50) NOT NULL DEFAULT 'pf2e',
+    UNIQUE (source, foundry_id, pack_path)
+);
+
+CREATE TABLE hazard_traits (
+    id SERIAL PRIMARY KEY,
+    hazard_id INTEGER REFERENCES hazards(id) ON DELETE CASCADE,
+    trait VARCHAR(50)
+);
+
+CREATE TABLE hazard_immunities (
+    id SERIAL PRIMARY KEY,
+    hazard_id INTEGER REFERENCES hazards(id) ON DELETE CASCADE,
+    immunity VARCHAR(100)
+);
+
+CREATE TABLE hazard_actions (
+    id SERIAL PRIMARY KEY,
+    hazard_id INTEGER REFERENCES hazards(id) ON DELETE CASCADE,
+    action_type VARCHAR(20) CHECK (action_type IN ('action', 'free_action', 'reaction', 'passive')),
+    name VARCHAR(250),
+    text TEXT,
+    actions VARCHAR(100)
+);
+
+CREATE TABLE hazard_attacks (
+    id SERIAL PRIMARY KEY,
+    hazard_id INTEGER REFERENCES hazards(id) ON DELETE CASCADE,
+    attack_category VARCHAR(20) CHECK (attack_category IN ('melee', 'ranged')),
+    name VARCHAR(100),
+    attack_type VARCHAR(50),
+    to_hit_bonus VARCHAR(50)
+);
+
+CREATE TABLE hazard_attack_damage_blocks (
+    id SERIAL PRIMARY KEY,
+    attack_id INTEGER REFERENCES hazard_attacks(id) ON DELETE CASCADE,
+    damage_roll VARCHAR(50),
+    damage_type VARCHAR(50)
+);
+
+-- Every file the last syncs read, keyed by the data source and its path
+-- inside that source's archive. Files whose content_hash is unchanged are not
+-- parsed again; their records are marked seen through foundry_id and
+-- pack_path instead.
+CREATE TABLE source_files (
+    path TEXT NOT NULL,
+    foundry_id VARCHAR(50) NOT NULL,
+    pack_path TEXT NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    source VARCHAR(50) NOT NULL DEFAULT 'pf2e',
+    PRIMARY KEY (source, path)
+);
+
+-- The upstream data this generation was last synced from, one row per data
+-- source. It swaps in and out with the rest of the generation, so a rollback
+-- restores it too. release is NULL when the sync followed a branch instead of
+-- a tag.
+CREATE TABLE data_version (
+    release TEXT,
+    source_version TEXT NOT NULL,
+    loaded_at TIMESTAMPTZ NOT NULL,
+    source VARCHAR(50) NOT NULL DEFAULT 'pf2e' PRIMARY KEY
+);
@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTables(t *testing.T) {
+	tables := Tables()
+	if len(tables) == 0 || tables[0] != "monsters" {
+		t.Fatalf("Expected monsters to be created first, got %v", tables)
+	}
+	position := make(map[string]int, len(tables))
+	for i, table := range tables {
+		position[table] = i
+	}
+	for _, pair := range [][2]string{
+		{"monsters", "monster_traits"},
+		{"spells", "focus_spell_casting_spells"},
+		{"hazards", "hazard_attack_damage_blocks"},
+	} {
+		parent, okParent := position[pair[0]]
+		child, okChild := position[pair[1]]
+		if !okParent || !okChild || parent > child {
+			t.Errorf("Expected %s before %s in %v", pair[0], pair[1], tables)
+		}
+	}
+}
+
+var ownedByMonster = regexp.MustCompile(`(?ms)^CREATE TABLE (\w+) \([^;]*?^\s+monster_id INTEGER REFERENCES monsters\(id\)`)
+
+func TestMonsterChildrenCleared(t *testing.T) {
+	queries, err := os.ReadFile("../queries/insert_monster.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := strings.Index(string(queries), "-- name: DeleteMonsterChildren")
+	if start < 0 {
+		t.Fatal("DeleteMonsterChildren not found")
+	}
+	deleteChildren, _, _ := strings.Cut(string(queries)[start:], ";")
+	matches := ownedByMonster.FindAllStringSubmatch(DDL, -1)
+	if len(matches) == 0 {
+		t.Fatal("Expected tables owned by monsters")
+	}
+	for _, match := range matches {
+		if !strings.Contains(deleteChildren, "DELETE FROM "+match[1]+" WHERE monster_id = $1") {
+			t.Errorf("Expected DeleteMonsterChildren to clear %s", match[1])
+		}
+	}
+}
+
+var creatingDDL = regexp.MustCompile(`(?:CREATE TABLE|CREATE INDEX|ADD COLUMN) (?:IF NOT EXISTS)?`)
+
+func TestMigrationsRepeatable(t *testing.T) {
+	for name, ddl := range map[string]string{"migrate.sql": MigrateDDL, "ops.sql": OpsDDL} {
+		for _, statement := range creatingDDL.FindAllString(ddl, -1) {
+			if !strings.Contains(statement, "IF NOT EXISTS") {
+				t.Errorf("Expected %s to only create tables, indexes and columns IF NOT EXISTS, found %q", name, statement)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package structs
+
+// EncounterCreature is a single creature priced against a party level.
+type EncounterCreature struct {
+	MonsterID       int32  `json:"monster_id"`
+	Kind            string `json:"kind"`
+	Name            string `json:"name"`
+	Level           int    `json:"level"`
+	Template        string `json:"template,omitempty"`
+	LevelDifference int    `json:"level_difference"`
+	XP              int    `json:"xp"`
+}
+
+// EncounterCost is the XP total of a list of creatures for a given party level.
+type EncounterCost struct {
+	PartyLevel int                 `json:"party_level"`
+	TotalXP    int                 `json:"total_xp"`
+	Creatures  []EncounterCreature `json:"creatures"`
+}
+
+// EncounterEntry is one line of a proposed encounter: a monster or hazard,
+// how many copies of it are present and an optional elite or weak template.
+// Kind is "creature" or "hazard"; empty means creature.
+type EncounterEntry struct {
+	MonsterID int32  `json:"id"`
+	Count     int    `json:"count"`
+	Kind      string `json:"kind,omitempty"`
+	Template  string `json:"template,omitempty"`
+}
+
+// EncounterWarning flags something a GM should look at before running the
+// encounter.
+type EncounterWarning struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	MonsterID int32  `json:"monster_id,omitempty"`
+}
+
+// ThreatBudget is the XP budget for one threat tier and how far the
+// encounter is from it. A negative Remaining means the encounter is over.
+type ThreatBudget struct {
+	Threat    string `json:"threat"`
+	Budget    int    `json:"budget"`
+	Remaining int    `json:"remaining"`
+}
+
+// EncounterEvaluation rates a proposed encounter against a party.
+type EncounterEvaluation struct {
+	PartyLevel int                 `json:"party_level"`
+	PartySize  int                 `json:"party_size"`
+	TotalXP    int                 `json:"total_xp"`
+	Threat     string              `json:"threat"`
+	Budgets    []ThreatBudget      `json:"budgets"`
+	Creatures  []EncounterCreature `json:"creatures"`
+	Warnings   []EncounterWarning  `json:"warnings"`
+}
+
+// EncounterSuggestion is one generated encounter and how close it lands to
+// the requested budget.
+type EncounterSuggestion struct {
+	TotalXP   int                 `json:"total_xp"`
+	Budget    int                 `json:"budget"`
+	Creatures []EncounterCreature `json:"creatures"`
+}
+
+// TierBudget is the party's XP budget for one threat tier together with the
+// per-character adjustment that produced it.
+type TierBudget struct {
+	Threat              string `json:"threat"`
+	Budget              int    `json:"budget"`
+	CharacterAdjustment int    `json:"character_adjustment"`
+}
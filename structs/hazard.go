@@ -0,0 +1,31 @@
+package structs
+
+// Hazard is a trap, haunt or environmental danger. Simple hazards trigger once;
+// complex hazards act in initiative following their routine.
+type Hazard struct {
+	Name        string       `json:"name"`
+	Level       int          `json:"level"`
+	Complex     bool         `json:"complex"`
+	Traits      Traits       `json:"traits"`
+	Stealth     Stealth      `json:"stealth"`
+	Disable     string       `json:"disable"`
+	AClass      AC           `json:"ac"`
+	Hardness    int          `json:"hardness"`
+	HP          HP           `json:"hp"`
+	Saves       Saves        `json:"saves"`
+	Immunities  []string     `json:"immunities"`
+	Description string       `json:"description"`
+	Routine     string       `json:"routine"`
+	Reset       string       `json:"reset"`
+	Actions     []Action     `json:"actions"`
+	FreeActions []FreeAction `json:"free_actions"`
+	Reactions   []Reaction   `json:"reactions"`
+	Passives    []Passive    `json:"passives"`
+	Melees      []Attack     `json:"melees"`
+	Ranged      []Attack     `json:"ranged"`
+}
+
+type Stealth struct {
+	Value  int    `json:"value"`
+	Detail string `json:"detail"`
+}
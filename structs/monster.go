@@ -4,7 +4,8 @@ type Monster struct {
 	Name         string
 	Traits       Traits
 	Attributes   Attributes
-	Level        string
+	Level        int
+	Template     string
 	Saves        Saves
 	AClass       AC
 	HP           HP
@@ -65,7 +66,7 @@ type HP struct {
 	Value  int
 }
 type AC struct {
-	Value  string
+	Value  int
 	Detail string
 }
 type Traits struct {
@@ -84,19 +85,19 @@ type SkillSpecial struct {
 	Predicates []string
 }
 type Attributes struct {
-	Str string
-	Dex string
-	Con string
-	Wis string
-	Int string
-	Cha string
+	Str int
+	Dex int
+	Con int
+	Wis int
+	Int int
+	Cha int
 }
 type Saves struct {
-	Fort       string
+	Fort       int
 	FortDetail string
-	Ref        string
+	Ref        int
 	RefDetail  string
-	Will       string
+	Will       int
 	WillDetail string // exceptions per type
 	Exception  string // overall exceptions
 }
@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Errors an ArchiveEntryError wraps to say why an entry was refused.
+var (
+	ErrArchivePath      = errors.New("path is absolute or leaves the destination")
+	ErrArchiveLink      = errors.New("links are not extracted")
+	ErrArchiveEntryType = errors.New("entry is not a file or directory")
+	ErrArchiveEntrySize = errors.New("entry is larger than the per-file limit")
+	ErrArchiveSize      = errors.New("archive is larger than the total limit")
+)
+
+// ArchiveEntryError names the archive entry that was refused.
+type ArchiveEntryError struct {
+	Entry string
+	Err   error
+}
+
+func (e *ArchiveEntryError) Error() string {
+	return fmt.Sprintf("refused archive entry %s: %v", e.Entry, e.Err)
+}
+
+func (e *ArchiveEntryError) Unwrap() error {
+	return e.Err
+}
+
+// archiveLimits caps what an archive may unpack to, in bytes. The sizes come
+// from the tar headers, which the tar reader holds each entry to.
+type archiveLimits struct {
+	entry int64
+	total int64
+}
+
+// defaultArchiveLimits leaves ample room for the Foundry repository, whose
+// largest files are a few megabytes and whose checkout is under 2 GiB.
+var defaultArchiveLimits = archiveLimits{entry: 64 << 20, total: 8 << 30}
+
+// check adds the size of header to *total and refuses it if it breaks a
+// limit.
+func (l archiveLimits) check(header *tar.Header, total *int64) error {
+	if header.Size > l.entry {
+		return &ArchiveEntryError{Entry: header.Name, Err: ErrArchiveEntrySize}
+	}
+	*total += header.Size
+	if *total > l.total {
+		return &ArchiveEntryError{Entry: header.Name, Err: ErrArchiveSize}
+	}
+	return nil
+}
+
+// packArchive streams the pack documents out of a .tar.gz of the Foundry
+// repository without extracting it, so a sync needs no writable disk for the
+// unpacked files.
+type packArchive struct {
+	// name identifies the archive in failures.
+	name string
+	// include and packs each restrict the documents to packs matching their
+	// patterns when set: include is the pack list of the data source, packs
+	// the filter of an import, see ImportOptions.Packs.
+	include []string
+	packs   []string
+	limits  archiveLimits
+	// root is the directory the archive's entries sit under, such as
+	// foundryvtt-pf2e-4cbdaa3. It is set once documents has read an entry.
+	root string
+}
+
+func newPackArchive(name string, packs []string) *packArchive {
+	return &packArchive{name: name, packs: packs, limits: defaultArchiveLimits}
+}
+
+// documents yields every .json entry in a packs folder of the archive read
+// from r. Entries are keyed relative to the archive root like unpacked files
+// are. Each entry is read before it is yielded since the stream cannot seek
+// back. An entry over the size limit fails on its own; a read error, the
+// total limit or cancelling ctx ends the stream with one document that fails
+// with the error.
+func (a *packArchive) documents(ctx context.Context, r io.Reader) iter.Seq[sourceDocument] {
+	return func(yield func(sourceDocument) bool) {
+		fail := func(name string, err error) bool {
+			return yield(sourceDocument{path: name, key: name, read: func() ([]byte, error) { return nil, err }})
+		}
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			fail(a.name, fmt.Errorf("failed to read archive %w", err))
+			return
+		}
+		defer gz.Close()
+		tr := tar.NewReader(gz)
+		var total int64
+		for {
+			if err := ctx.Err(); err != nil {
+				fail(a.name, err)
+				return
+			}
+			header, err := tr.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				fail(a.name, fmt.Errorf("failed to read archive %w", err))
+				return
+			}
+			name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+			// GitHub tarballs open with a pax_global_header entry holding
+			// the commit, which is not part of the tree.
+			if a.root == "" && (header.Typeflag == tar.TypeDir || header.Typeflag == tar.TypeReg) {
+				a.root, _, _ = strings.Cut(name, "/")
+			}
+			if header.Typeflag != tar.TypeReg || !strings.HasSuffix(name, ".json") || !inPacks(name) {
+				continue
+			}
+			if !packWanted(PackPath(name), a.include) || !packWanted(PackPath(name), a.packs) {
+				continue
+			}
+			if err := a.limits.check(header, &total); err != nil {
+				if errors.Is(err, ErrArchiveSize) {
+					fail(a.name, err)
+					return
+				}
+				if !fail(name, err) {
+					return
+				}
+				continue
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				fail(a.name, fmt.Errorf("failed to read %s from archive %w", name, err))
+				return
+			}
+			key := name
+			if _, rel, ok := strings.Cut(name, "/"); ok {
+				key = rel
+			}
+			doc := sourceDocument{path: name, key: key, read: func() ([]byte, error) { return data, nil }}
+			if !yield(doc) {
+				return
+			}
+		}
+	}
+}
+
+// ExtractArchive unpacks an archive of the Foundry repository into destDir
+// for tools that read the files from disk. Syncs stream the archive instead.
+// Refused entries are reported as an *ArchiveEntryError.
+func ExtractArchive(tarFile string, destDir string) error {
+	return extractTarball(tarFile, destDir, defaultArchiveLimits)
+}
+
+// extractTarball unpacks tarFile under destDir. Every path is resolved inside
+// destDir, even through symlinks already there, and links in the archive are
+// refused rather than followed. Files get 0644 and directories 0755 whatever
+// modes the archive records. Extraction stops at the first refused entry,
+// leaving what was unpacked before it.
+func extractTarball(tarFile string, destDir string, limits archiveLimits) error {
+	file, err := os.Open(tarFile)
+	if err != nil {
+		return fmt.Errorf("failed to open tar file: %w", err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return err
+	}
+	root, err := os.OpenRoot(destDir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	var total int64
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader:
+			// GitHub records the commit in a pax header with no file.
+			continue
+		case tar.TypeDir, tar.TypeReg:
+		case tar.TypeSymlink, tar.TypeLink:
+			return &ArchiveEntryError{Entry: header.Name, Err: ErrArchiveLink}
+		default:
+			return &ArchiveEntryError{Entry: header.Name, Err: ErrArchiveEntryType}
+		}
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return &ArchiveEntryError{Entry: header.Name, Err: ErrArchivePath}
+		}
+		if header.Typeflag == tar.TypeDir {
+			if err := mkdirAllIn(root, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := limits.check(header, &total); err != nil {
+			return err
+		}
+		if err := mkdirAllIn(root, filepath.Dir(name)); err != nil {
+			return err
+		}
+		if err := writeTarEntry(root, name, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// mkdirAllIn creates dir and any missing parents inside root.
+func mkdirAllIn(root *os.Root, dir string) error {
+	dir = filepath.Clean(dir)
+	if dir == "." {
+		return nil
+	}
+	if err := mkdirAllIn(root, filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := root.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+// writeTarEntry copies the current entry to name inside root, closing the
+// file before the next entry is read.
+func writeTarEntry(root *os.Root, name string, r io.Reader) error {
+	outFile, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+	if _, err := io.Copy(outFile, r); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return outFile.Close()
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+type archiveEntry struct {
+	header tar.Header
+	body   string
+}
+
+// writeEntries writes an archive of exactly entries, which writeArchive
+// cannot produce from a directory.
+func writeEntries(t *testing.T, entries ...archiveEntry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "crafted.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := entry.header
+		if header.Typeflag == tar.TypeReg {
+			header.Size = int64(len(entry.body))
+		}
+		if header.Mode == 0 && header.Typeflag != tar.TypeXGlobalHeader {
+			header.Mode = 0o644
+		}
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func fileEntry(name string, body string) archiveEntry {
+	return archiveEntry{header: tar.Header{Name: name, Typeflag: tar.TypeReg}, body: body}
+}
+
+func readArchive(t *testing.T, ctx context.Context, archive *packArchive, path string) []sourceDocument {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return slices.Collect(archive.documents(ctx, f))
+}
+
+func TestPackArchiveDocuments(t *testing.T) {
+	path := writeArchive(t, writeArchiveTree(t))
+	archive := newPackArchive(path, nil)
+	docs := readArchive(t, context.Background(), archive, path)
+	if archive.root != "foundryvtt-pf2e-abc123" {
+		t.Errorf("Expected the archive root to be found, got %q", archive.root)
+	}
+	var keys []string
+	for _, doc := range docs {
+		keys = append(keys, doc.key)
+		data, err := doc.read()
+		if err != nil || !bytes.Contains(data, []byte("loot")) {
+			t.Errorf("%s: unexpected content %q, %v", doc.path, data, err)
+		}
+	}
+	slices.Sort(keys)
+	want := []string{
+		"packs/age-of-ashes-bestiary/book-1-hellknight-hill/charau-ka.json",
+		"packs/equipment/sword.json",
+		"packs/pathfinder-bestiary/goblin.json",
+	}
+	if !slices.Equal(keys, want) {
+		t.Errorf("Expected only the pack documents, got %v", keys)
+	}
+
+	filtered := readArchive(t, context.Background(), newPackArchive(path, []string{"*-bestiary"}), path)
+	if len(filtered) != 2 {
+		t.Errorf("Expected the bestiary documents, got %d", len(filtered))
+	}
+}
+
+func TestPackArchiveDocumentsFailures(t *testing.T) {
+	notGzip := writeArchiveTree(t) + "/not-an-archive.tar.gz"
+	if err := os.WriteFile(notGzip, []byte("<html>Not Found</html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	docs := readArchive(t, context.Background(), newPackArchive(notGzip, nil), notGzip)
+	if len(docs) != 1 || docs[0].path != notGzip {
+		t.Fatalf("Expected a single failure for the archive, got %+v", docs)
+	}
+	if _, err := docs[0].read(); err == nil {
+		t.Error("Expected the failure to carry the read error")
+	}
+
+	path := writeArchive(t, writeArchiveTree(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	docs = readArchive(t, ctx, newPackArchive(path, nil), path)
+	if len(docs) != 1 {
+		t.Fatalf("Expected the stream to stop on cancel, got %+v", docs)
+	}
+	if _, err := docs[0].read(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
+
+func TestIngestArchive(t *testing.T) {
+	path := writeArchive(t, writeArchiveTree(t))
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	archive := newPackArchive(path, nil)
+	// Loot is never stored, so the documents only go through the parse
+	// workers.
+	report := ingest(context.Background(), config.Config{}, defaultSource(), archive.documents(context.Background(), f), time.Now(), IngestOptions{ParseWorkers: 2}, nil)
+	if report.Files != 3 || report.Failed != 0 || report.Skipped != 3 || len(report.sources) != 3 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	for _, source := range report.sources {
+		if source.doc.Path != "foundryvtt-pf2e-abc123/"+source.key {
+			t.Errorf("Expected %s to keep its archive path, got %s", source.key, source.doc.Path)
+		}
+	}
+}
+
+func TestPackArchiveDocumentsSizeLimits(t *testing.T) {
+	path := writeEntries(t,
+		fileEntry("pf2e/packs/bestiary/huge.json", `{"type": "npc", "padding": "xxxxxxxxxxxxxxxx"}`),
+		fileEntry("pf2e/packs/bestiary/goblin.json", `{"type": "npc"}`),
+	)
+	archive := newPackArchive(path, nil)
+	archive.limits = archiveLimits{entry: 32, total: 1 << 20}
+	docs := readArchive(t, context.Background(), archive, path)
+	if len(docs) != 2 {
+		t.Fatalf("Expected the oversized entry to fail on its own, got %+v", docs)
+	}
+	if _, err := docs[0].read(); !errors.Is(err, ErrArchiveEntrySize) || docs[0].path != "pf2e/packs/bestiary/huge.json" {
+		t.Errorf("Expected %s to be refused for its size, got %v", docs[0].path, err)
+	}
+	if _, err := docs[1].read(); err != nil {
+		t.Errorf("Unexpected error for %s: %v", docs[1].path, err)
+	}
+
+	archive = newPackArchive(path, nil)
+	archive.limits = archiveLimits{entry: 1 << 20, total: 50}
+	docs = readArchive(t, context.Background(), archive, path)
+	if len(docs) != 2 || docs[1].path != path {
+		t.Fatalf("Expected the stream to end at the total limit, got %+v", docs)
+	}
+	if _, err := docs[1].read(); !errors.Is(err, ErrArchiveSize) {
+		t.Errorf("Expected the total limit error, got %v", err)
+	}
+}
+
+func TestExtractTarball(t *testing.T) {
+	path := writeEntries(t,
+		archiveEntry{header: tar.Header{Name: "pax_global_header", Typeflag: tar.TypeXGlobalHeader, PAXRecords: map[string]string{"comment": "abc123"}}},
+		fileEntry("pf2e/packs/bestiary/goblin.json", `{"type": "npc"}`),
+		archiveEntry{header: tar.Header{Name: "pf2e/bin/run.sh", Typeflag: tar.TypeReg, Mode: 0o4777}, body: "#!/bin/sh"},
+	)
+	dest := filepath.Join(t.TempDir(), "files")
+	if err := extractTarball(path, dest, defaultArchiveLimits); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "pf2e", "packs", "bestiary", "goblin.json"))
+	if err != nil || string(data) != `{"type": "npc"}` {
+		t.Errorf("Unexpected content %q, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(dest, "pf2e", "bin", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&^0o644 != 0 {
+		t.Errorf("Expected the archive's mode to be ignored, got %v", info.Mode())
+	}
+}
+
+func TestExtractTarballRefusesEntries(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  archiveEntry
+		limits archiveLimits
+		want   error
+	}{
+		{"parent", fileEntry("../evil.json", "{}"), defaultArchiveLimits, ErrArchivePath},
+		{"nested parent", fileEntry("pf2e/../../evil.json", "{}"), defaultArchiveLimits, ErrArchivePath},
+		{"absolute", fileEntry("/tmp/evil.json", "{}"), defaultArchiveLimits, ErrArchivePath},
+		{"symlink", archiveEntry{header: tar.Header{Name: "pf2e/link", Typeflag: tar.TypeSymlink, Linkname: "/etc"}}, defaultArchiveLimits, ErrArchiveLink},
+		{"hard link", archiveEntry{header: tar.Header{Name: "pf2e/link", Typeflag: tar.TypeLink, Linkname: "../evil.json"}}, defaultArchiveLimits, ErrArchiveLink},
+		{"fifo", archiveEntry{header: tar.Header{Name: "pf2e/fifo", Typeflag: tar.TypeFifo}}, defaultArchiveLimits, ErrArchiveEntryType},
+		{"entry size", fileEntry("pf2e/big.json", "0123456789"), archiveLimits{entry: 4, total: 100}, ErrArchiveEntrySize},
+		{"total size", fileEntry("pf2e/big.json", "0123456789"), archiveLimits{entry: 100, total: 4}, ErrArchiveSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeEntries(t, fileEntry("pf2e/ok.json", "{}"), tt.entry)
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "files")
+			err := extractTarball(path, dest, tt.limits)
+			var entryErr *ArchiveEntryError
+			if !errors.As(err, &entryErr) || !errors.Is(err, tt.want) {
+				t.Fatalf("Expected an entry error wrapping %v, got %v", tt.want, err)
+			}
+			if entryErr.Entry != tt.entry.header.Name {
+				t.Errorf("Expected the error to name %s, got %s", tt.entry.header.Name, entryErr.Entry)
+			}
+			if _, err := os.Stat(filepath.Join(parent, "evil.json")); err == nil {
+				t.Error("Expected nothing to be written outside the destination")
+			}
+		})
+	}
+}
+
+func TestExtractTarballStaysInsideExistingSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	dest := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dest, "pf2e")); err != nil {
+		t.Skip("symlinks are unavailable:", err)
+	}
+	path := writeEntries(t, fileEntry("pf2e/evil.json", "{}"))
+	if err := extractTarball(path, dest, defaultArchiveLimits); err == nil {
+		t.Error("Expected writing through a symlink out of the destination to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "evil.json")); err == nil {
+		t.Error("Expected nothing to be written outside the destination")
+	}
+}
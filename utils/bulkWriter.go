@@ -0,0 +1,455 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// monsterRows accumulates the child rows of a batch of monsters so each table
+// is loaded with a single COPY.
+type monsterRows struct {
+	traits         []writeMonsters.CopyMonsterTraitsParams
+	immunities     []writeMonsters.CopyMonsterImmunitiesParams
+	modifiers      []writeMonsters.CopyMonsterDamageModifiersParams
+	exceptions     []writeMonsters.CopyMonsterModifierExceptionsParams
+	doubles        []writeMonsters.CopyMonsterModifierDoublesParams
+	languages      []writeMonsters.CopyMonsterLanguagesParams
+	senses         []writeMonsters.CopyMonsterSensesParams
+	skills         []writeMonsters.CopyMonsterSkillsParams
+	skillSpecials  []writeMonsters.CopyMonsterSkillSpecialsParams
+	movements      []writeMonsters.CopyMonsterMovementsParams
+	actions        []writeMonsters.CopyMonsterActionsParams
+	actionTraits   []writeMonsters.CopyMonsterActionTraitsParams
+	attacks        []writeMonsters.CopyMonsterAttacksParams
+	damageBlocks   []writeMonsters.CopyAttackDamageBlocksParams
+	items          []writeMonsters.CopyItemsParams
+	itemTraits     []writeMonsters.CopyItemTraitsParams
+	spells         []writeMonsters.CopySpellsParams
+	spellAreas     []writeMonsters.CopySpellAreasParams
+	durations      []writeMonsters.CopySpellDurationsParams
+	defenses       []writeMonsters.CopySpellDefensesParams
+	focus          []writeMonsters.CopyFocusSpellCastingParams
+	focusSpells    []writeMonsters.CopyFocusSpellCastingSpellsParams
+	innate         []writeMonsters.CopyInnateSpellCastingParams
+	innateUses     []writeMonsters.CopyInnateSpellUsesParams
+	prepared       []writeMonsters.CopyPreparedSpellCastingParams
+	preparedSlots  []writeMonsters.CopyPreparedSlotsParams
+	spontaneous    []writeMonsters.CopySpontaneousSpellCastingParams
+	slots          []writeMonsters.CopySpontaneousSlotsParams
+	spellList      []writeMonsters.CopySpontaneousSpellListParams
+	modifierIDs    idPool
+	skillIDs       idPool
+	actionIDs      idPool
+	attackIDs      idPool
+	focusIDs       idPool
+	innateIDs      idPool
+	preparedIDs    idPool
+	spontaneousIDs idPool
+}
+
+// idPool hands out ids reserved with AllocateIDs, so child rows can point at
+// their parent before either is written.
+type idPool []int32
+
+func (p *idPool) next() int32 {
+	id := (*p)[0]
+	*p = (*p)[1:]
+	return id
+}
+
+func reserveIDs(ctx context.Context, queries *writeMonsters.Queries, table string, count int) (idPool, error) {
+	if count == 0 {
+		return nil, nil
+	}
+	ids, err := queries.AllocateIDs(ctx, writeMonsters.AllocateIDsParams{TableName: table, IDCount: int32(count)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve %d ids for %s %w", count, table, err)
+	}
+	return ids, nil
+}
+
+// newMonsterRows reserves ids for every parent row in the batch. ids holds the
+// database id of each record.
+func newMonsterRows(ctx context.Context, queries *writeMonsters.Queries, records []MonsterRecord, ids []int32) (*monsterRows, error) {
+	var modifiers, skills, actions, attacks, focus, innate, prepared, spontaneous int
+	for _, record := range records {
+		m := record.Monster
+		modifiers += len(m.Weaknesses) + len(m.Resistances)
+		skills += len(m.Skills)
+		actions += len(m.Actions) + len(m.Reactions) + len(m.Passives)
+		attacks += len(m.Melees) + len(m.Ranged)
+		focus += len(m.SpellCasting.FocusSpellCasting)
+		innate += len(m.SpellCasting.InnateSpellCasting)
+		prepared += len(m.SpellCasting.PreparedSpellCasting)
+		spontaneous += len(m.SpellCasting.SpontaneousSpellCasting)
+	}
+	rows := &monsterRows{}
+	var err error
+	if rows.modifierIDs, err = reserveIDs(ctx, queries, "monster_damage_modifiers", modifiers); err != nil {
+		return nil, err
+	}
+	if rows.skillIDs, err = reserveIDs(ctx, queries, "monster_skills", skills); err != nil {
+		return nil, err
+	}
+	if rows.actionIDs, err = reserveIDs(ctx, queries, "monster_actions", actions); err != nil {
+		return nil, err
+	}
+	if rows.attackIDs, err = reserveIDs(ctx, queries, "monster_attacks", attacks); err != nil {
+		return nil, err
+	}
+	if rows.focusIDs, err = reserveIDs(ctx, queries, "focus_spell_casting", focus); err != nil {
+		return nil, err
+	}
+	if rows.innateIDs, err = reserveIDs(ctx, queries, "innate_spell_casting", innate); err != nil {
+		return nil, err
+	}
+	if rows.preparedIDs, err = reserveIDs(ctx, queries, "prepared_spell_casting", prepared); err != nil {
+		return nil, err
+	}
+	if rows.spontaneousIDs, err = reserveIDs(ctx, queries, "spontaneous_spell_casting", spontaneous); err != nil {
+		return nil, err
+	}
+	for i, record := range records {
+		rows.add(record.Monster, ids[i])
+	}
+	return rows, nil
+}
+
+func (r *monsterRows) add(monster structs.Monster, id int32) {
+	monsterID := NewInt4(int(id))
+	for _, trait := range monster.Traits.TraitList {
+		r.traits = append(r.traits, writeMonsters.CopyMonsterTraitsParams{MonsterID: monsterID, Trait: NewText(trait)})
+	}
+	for _, immunity := range monster.Immunities {
+		r.immunities = append(r.immunities, writeMonsters.CopyMonsterImmunitiesParams{MonsterID: monsterID, Immunity: NewText(immunity)})
+	}
+	r.addModifiers(monsterID, "weakness", monster.Weaknesses)
+	r.addModifiers(monsterID, "resistance", monster.Resistances)
+	for _, language := range monster.Languages {
+		r.languages = append(r.languages, writeMonsters.CopyMonsterLanguagesParams{MonsterID: monsterID, Language: NewText(language)})
+	}
+	for _, sense := range monster.Senses {
+		r.senses = append(r.senses, writeMonsters.CopyMonsterSensesParams{
+			MonsterID: monsterID,
+			Name:      NewText(sense.Name),
+			Range:     NewText(sense.Range),
+			Acuity:    NewText(sense.Acuity),
+			Detail:    NewText(sense.Detail),
+		})
+	}
+	for _, skill := range monster.Skills {
+		skillID := r.skillIDs.next()
+		r.skills = append(r.skills, writeMonsters.CopyMonsterSkillsParams{
+			ID:        skillID,
+			MonsterID: monsterID,
+			Name:      NewText(skill.Name),
+			Value:     NewInt4(skill.Value),
+		})
+		for _, special := range skill.Specials {
+			r.skillSpecials = append(r.skillSpecials, writeMonsters.CopyMonsterSkillSpecialsParams{
+				SkillID:    NewInt4(int(skillID)),
+				Value:      NewInt4(special.Value),
+				Label:      NewText(special.Label),
+				Predicates: special.Predicates,
+			})
+		}
+	}
+	for _, movement := range monster.Movements {
+		r.movements = append(r.movements, writeMonsters.CopyMonsterMovementsParams{
+			MonsterID:    monsterID,
+			MovementType: NewText(movement.Type),
+			Speed:        NewText(movement.Speed),
+			Notes:        NewText(movement.Notes),
+		})
+	}
+	for _, action := range monster.Actions {
+		r.addAction(writeMonsters.CopyMonsterActionsParams{
+			MonsterID:  monsterID,
+			ActionType: NewText("action"),
+			Name:       NewText(action.Name),
+			Text:       NewText(action.Text),
+			Actions:    NewText(action.Actions),
+			Category:   NewText(action.Category),
+			Rarity:     NewText(action.Rarity),
+		}, action.Traits)
+	}
+	for _, reaction := range monster.Reactions {
+		r.addAction(writeMonsters.CopyMonsterActionsParams{
+			MonsterID:  monsterID,
+			ActionType: NewText("reaction"),
+			Name:       NewText(reaction.Name),
+			Text:       NewText(reaction.Text),
+			Category:   NewText(reaction.Category),
+			Rarity:     NewText(reaction.Rarity),
+		}, reaction.Traits)
+	}
+	for _, passive := range monster.Passives {
+		r.addAction(writeMonsters.CopyMonsterActionsParams{
+			MonsterID:  monsterID,
+			ActionType: NewText("passive"),
+			Name:       NewText(passive.Name),
+			Text:       NewText(passive.Text),
+			Category:   NewText(passive.Category),
+			Rarity:     NewText(passive.Rarity),
+			Dc:         NewText(passive.DC),
+		}, passive.Traits)
+	}
+	r.addAttacks(monsterID, "melee", monster.Melees)
+	r.addAttacks(monsterID, "ranged", monster.Ranged)
+	for _, item := range monster.Inventory {
+		itemID := uuid.New().String()
+		r.items = append(r.items, writeMonsters.CopyItemsParams{
+			ID:          itemID,
+			MonsterID:   monsterID,
+			Name:        NewText(item.Name),
+			Category:    NewText(item.Category),
+			Description: NewText(item.Description),
+			Level:       NewText(item.Level),
+			Rarity:      NewText(item.Rarity),
+			Bulk:        NewText(item.Bulk),
+			Quantity:    NewText(item.Quantity),
+			PricePer:    NewInt4(item.Price.Per),
+			PriceCp:     NewInt4(item.Price.CP),
+			PriceSp:     NewInt4(item.Price.SP),
+			PriceGp:     NewInt4(item.Price.GP),
+			PricePp:     NewInt4(item.Price.PP),
+		})
+		for _, trait := range item.Traits {
+			r.itemTraits = append(r.itemTraits, writeMonsters.CopyItemTraitsParams{ItemID: NewText(itemID), Trait: NewText(trait)})
+		}
+	}
+	r.addSpellCasting(monsterID, monster.SpellCasting)
+}
+
+func (r *monsterRows) addModifiers(monsterID pgtype.Int4, category string, modifiers []structs.DamageModifierBlock) {
+	for _, modifier := range modifiers {
+		modifierID := r.modifierIDs.next()
+		r.modifiers = append(r.modifiers, writeMonsters.CopyMonsterDamageModifiersParams{
+			ID:               modifierID,
+			MonsterID:        monsterID,
+			ModifierCategory: NewText(category),
+			Value:            NewInt4(modifier.Value),
+			DamageType:       NewText(modifier.Type),
+		})
+		for _, exception := range modifier.Exceptions {
+			r.exceptions = append(r.exceptions, writeMonsters.CopyMonsterModifierExceptionsParams{ModifierID: NewInt4(int(modifierID)), Exception: NewText(exception)})
+		}
+		for _, double := range modifier.Double {
+			r.doubles = append(r.doubles, writeMonsters.CopyMonsterModifierDoublesParams{ModifierID: NewInt4(int(modifierID)), DoubleValue: NewText(double)})
+		}
+	}
+}
+
+func (r *monsterRows) addAction(action writeMonsters.CopyMonsterActionsParams, traits []string) {
+	action.ID = r.actionIDs.next()
+	r.actions = append(r.actions, action)
+	for _, trait := range traits {
+		r.actionTraits = append(r.actionTraits, writeMonsters.CopyMonsterActionTraitsParams{MonsterActionID: NewInt4(int(action.ID)), Trait: NewText(trait)})
+	}
+}
+
+func (r *monsterRows) addAttacks(monsterID pgtype.Int4, category string, attacks []structs.Attack) {
+	for _, attack := range attacks {
+		attackID := r.attackIDs.next()
+		r.attacks = append(r.attacks, writeMonsters.CopyMonsterAttacksParams{
+			ID:                  attackID,
+			MonsterID:           monsterID,
+			AttackCategory:      NewText(category),
+			Name:                NewText(attack.Name),
+			AttackType:          NewText(attack.Type),
+			ToHitBonus:          NewText(attack.ToHitBonus),
+			EffectsCustomString: NewText(attack.Effects.CustomString),
+			EffectsValues:       attack.Effects.Value,
+		})
+		for _, block := range attack.DamageBlocks {
+			r.damageBlocks = append(r.damageBlocks, writeMonsters.CopyAttackDamageBlocksParams{
+				AttackID:   NewInt4(int(attackID)),
+				DamageRoll: NewText(block.DamageRoll),
+				DamageType: NewText(block.DamageType),
+			})
+		}
+	}
+}
+
+// addSpellCasting adds the monster's spellcasting blocks. Every spell a block
+// lists gets its own row, owned by the monster.
+func (r *monsterRows) addSpellCasting(monsterID pgtype.Int4, casting structs.SpellCasting) {
+	for _, block := range casting.InnateSpellCasting {
+		castingID := r.innateIDs.next()
+		r.innate = append(r.innate, writeMonsters.CopyInnateSpellCastingParams{
+			ID:             castingID,
+			MonsterID:      monsterID,
+			Dc:             NewInt4(block.DC),
+			Tradition:      NewText(block.Tradition),
+			Mod:            NewText(block.Mod),
+			SpellcastingID: NewText(block.ID),
+			Description:    NewText(block.Description),
+			Name:           NewText(block.Name),
+		})
+		for _, use := range block.SpellUses {
+			r.innateUses = append(r.innateUses, writeMonsters.CopyInnateSpellUsesParams{
+				InnateSpellCastingID: NewInt4(int(castingID)),
+				SpellID:              NewText(r.addSpell(monsterID, use.Spell)),
+				Level:                NewInt4(use.Level),
+				Uses:                 NewText(use.Uses),
+			})
+		}
+	}
+	for _, block := range casting.FocusSpellCasting {
+		castingID := r.focusIDs.next()
+		r.focus = append(r.focus, writeMonsters.CopyFocusSpellCastingParams{
+			ID:             castingID,
+			MonsterID:      monsterID,
+			Dc:             NewInt4(block.DC),
+			Mod:            NewText(block.Mod),
+			Tradition:      NewText(block.Tradition),
+			SpellcastingID: NewText(block.ID),
+			Name:           NewText(block.Name),
+			Description:    NewText(block.Description),
+			CastLevel:      NewText(block.CastLevel),
+		})
+		for _, spell := range block.FocusSpellList {
+			r.focusSpells = append(r.focusSpells, writeMonsters.CopyFocusSpellCastingSpellsParams{
+				FocusSpellCastingID: NewInt4(int(castingID)),
+				SpellID:             NewText(r.addSpell(monsterID, spell)),
+			})
+		}
+	}
+	for _, block := range casting.PreparedSpellCasting {
+		castingID := r.preparedIDs.next()
+		r.prepared = append(r.prepared, writeMonsters.CopyPreparedSpellCastingParams{
+			ID:             castingID,
+			MonsterID:      monsterID,
+			Dc:             NewInt4(block.DC),
+			Tradition:      NewText(block.Tradition),
+			Mod:            NewText(block.Mod),
+			SpellcastingID: NewText(block.ID),
+			Description:    NewText(block.Description),
+		})
+		for _, slot := range block.Slots {
+			r.preparedSlots = append(r.preparedSlots, writeMonsters.CopyPreparedSlotsParams{
+				PreparedSpellCastingID: NewInt4(int(castingID)),
+				Level:                  NewText(slot.Level),
+				SpellID:                NewText(r.addSpell(monsterID, slot.Spell)),
+			})
+		}
+	}
+	for _, block := range casting.SpontaneousSpellCasting {
+		castingID := r.spontaneousIDs.next()
+		r.spontaneous = append(r.spontaneous, writeMonsters.CopySpontaneousSpellCastingParams{
+			ID:        castingID,
+			MonsterID: monsterID,
+			Dc:        NewInt4(block.DC),
+			IDString:  NewText(block.ID),
+			Tradition: NewText(block.Tradition),
+		})
+		for _, spell := range block.SpellList {
+			r.spellList = append(r.spellList, writeMonsters.CopySpontaneousSpellListParams{
+				SpontaneousSpellCastingID: NewInt4(int(castingID)),
+				SpellID:                   NewText(r.addSpell(monsterID, spell)),
+			})
+		}
+		for _, slot := range block.Slots {
+			r.slots = append(r.slots, writeMonsters.CopySpontaneousSlotsParams{
+				SpontaneousSpellCastingID: NewInt4(int(castingID)),
+				Level:                     NewText(slot.Level),
+				Casts:                     NewText(slot.Casts),
+			})
+		}
+	}
+}
+
+// addSpell adds a spell row with a generated id, which it returns, along with
+// its area, duration and defense.
+func (r *monsterRows) addSpell(monsterID pgtype.Int4, spell structs.Spell) string {
+	spellID := uuid.New().String()
+	r.spells = append(r.spells, writeMonsters.CopySpellsParams{
+		ID:                          spellID,
+		MonsterID:                   monsterID,
+		FoundryID:                   NewText(spell.ID),
+		Name:                        NewText(spell.Name),
+		CastLevel:                   NewText(spell.CastLevel),
+		SpellBaseLevel:              NewText(spell.SpellBaseLevel),
+		Description:                 NewText(spell.Description),
+		Range:                       NewText(spell.Range),
+		CastTime:                    NewText(spell.CastTime),
+		CastRequirements:            NewText(spell.CastRequirements),
+		Rarity:                      NewText(spell.Rarity),
+		AtWill:                      pgtype.Bool{Bool: spell.AtWill, Valid: true},
+		SpellCastingBlockLocationID: NewText(spell.SpellCastingBlockLocationID),
+		Uses:                        NewText(spell.Uses),
+		Ritual:                      pgtype.Bool{Bool: spell.Ritual, Valid: true},
+		Targets:                     NewText(spell.Targets),
+	})
+	r.spellAreas = append(r.spellAreas, writeMonsters.CopySpellAreasParams{
+		SpellID:  NewText(spellID),
+		AreaType: NewText(spell.Area.Type),
+		Value:    NewText(spell.Area.Value),
+		Detail:   NewText(spell.Area.Detail),
+	})
+	r.durations = append(r.durations, writeMonsters.CopySpellDurationsParams{
+		SpellID:   NewText(spellID),
+		Sustained: pgtype.Bool{Bool: spell.Duration.Sustained, Valid: true},
+		Duration:  NewText(spell.Duration.Duration),
+	})
+	r.defenses = append(r.defenses, writeMonsters.CopySpellDefensesParams{
+		SpellID: NewText(spellID),
+		Save:    NewText(spell.Defense.Save),
+		Basic:   pgtype.Bool{Bool: spell.Defense.Basic, Valid: true},
+	})
+	return spellID
+}
+
+// load copies every table, parents before children. On failure it returns
+// the table that was rejected.
+func (r *monsterRows) load(ctx context.Context, queries *writeMonsters.Queries) (string, error) {
+	copies := []struct {
+		table string
+		rows  int
+		copy  func() (int64, error)
+	}{
+		{"monster_traits", len(r.traits), func() (int64, error) { return queries.CopyMonsterTraits(ctx, r.traits) }},
+		{"monster_immunities", len(r.immunities), func() (int64, error) { return queries.CopyMonsterImmunities(ctx, r.immunities) }},
+		{"monster_damage_modifiers", len(r.modifiers), func() (int64, error) { return queries.CopyMonsterDamageModifiers(ctx, r.modifiers) }},
+		{"monster_modifier_exceptions", len(r.exceptions), func() (int64, error) { return queries.CopyMonsterModifierExceptions(ctx, r.exceptions) }},
+		{"monster_modifier_doubles", len(r.doubles), func() (int64, error) { return queries.CopyMonsterModifierDoubles(ctx, r.doubles) }},
+		{"monster_languages", len(r.languages), func() (int64, error) { return queries.CopyMonsterLanguages(ctx, r.languages) }},
+		{"monster_senses", len(r.senses), func() (int64, error) { return queries.CopyMonsterSenses(ctx, r.senses) }},
+		{"monster_skills", len(r.skills), func() (int64, error) { return queries.CopyMonsterSkills(ctx, r.skills) }},
+		{"monster_skill_specials", len(r.skillSpecials), func() (int64, error) { return queries.CopyMonsterSkillSpecials(ctx, r.skillSpecials) }},
+		{"monster_movements", len(r.movements), func() (int64, error) { return queries.CopyMonsterMovements(ctx, r.movements) }},
+		{"monster_actions", len(r.actions), func() (int64, error) { return queries.CopyMonsterActions(ctx, r.actions) }},
+		{"monster_action_traits", len(r.actionTraits), func() (int64, error) { return queries.CopyMonsterActionTraits(ctx, r.actionTraits) }},
+		{"monster_attacks", len(r.attacks), func() (int64, error) { return queries.CopyMonsterAttacks(ctx, r.attacks) }},
+		{"attack_damage_blocks", len(r.damageBlocks), func() (int64, error) { return queries.CopyAttackDamageBlocks(ctx, r.damageBlocks) }},
+		{"items", len(r.items), func() (int64, error) { return queries.CopyItems(ctx, r.items) }},
+		{"item_traits", len(r.itemTraits), func() (int64, error) { return queries.CopyItemTraits(ctx, r.itemTraits) }},
+		{"spells", len(r.spells), func() (int64, error) { return queries.CopySpells(ctx, r.spells) }},
+		{"spell_areas", len(r.spellAreas), func() (int64, error) { return queries.CopySpellAreas(ctx, r.spellAreas) }},
+		{"spell_durations", len(r.durations), func() (int64, error) { return queries.CopySpellDurations(ctx, r.durations) }},
+		{"spell_defenses", len(r.defenses), func() (int64, error) { return queries.CopySpellDefenses(ctx, r.defenses) }},
+		{"innate_spell_casting", len(r.innate), func() (int64, error) { return queries.CopyInnateSpellCasting(ctx, r.innate) }},
+		{"innate_spell_uses", len(r.innateUses), func() (int64, error) { return queries.CopyInnateSpellUses(ctx, r.innateUses) }},
+		{"focus_spell_casting", len(r.focus), func() (int64, error) { return queries.CopyFocusSpellCasting(ctx, r.focus) }},
+		{"focus_spell_casting_spells", len(r.focusSpells), func() (int64, error) { return queries.CopyFocusSpellCastingSpells(ctx, r.focusSpells) }},
+		{"prepared_spell_casting", len(r.prepared), func() (int64, error) { return queries.CopyPreparedSpellCasting(ctx, r.prepared) }},
+		{"prepared_slots", len(r.preparedSlots), func() (int64, error) { return queries.CopyPreparedSlots(ctx, r.preparedSlots) }},
+		{"spontaneous_spell_casting", len(r.spontaneous), func() (int64, error) { return queries.CopySpontaneousSpellCasting(ctx, r.spontaneous) }},
+		{"spontaneous_slots", len(r.slots), func() (int64, error) { return queries.CopySpontaneousSlots(ctx, r.slots) }},
+		{"spontaneous_spell_list", len(r.spellList), func() (int64, error) { return queries.CopySpontaneousSpellList(ctx, r.spellList) }},
+	}
+	for _, c := range copies {
+		if c.rows == 0 {
+			continue
+		}
+		if _, err := c.copy(); err != nil {
+			return c.table, err
+		}
+	}
+	return "", nil
+}
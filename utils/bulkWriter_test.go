@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+)
+
+func TestMonsterRowsLinkChildrenToReservedIDs(t *testing.T) {
+	monster := structs.Monster{
+		Name:       "Goblin Warrior",
+		Weaknesses: []structs.DamageModifierBlock{{Type: "fire", Value: 5, Exceptions: []string{"magical"}, Double: []string{"critical-hits"}}},
+		Skills:     []structs.Skill{{Name: "stealth", Value: 5, Specials: []structs.SkillSpecial{{Label: "in forests", Value: 7}}}},
+		Actions:    []structs.Action{{Name: "Goblin Scuttle", Traits: []string{"goblin"}}},
+		Passives:   []structs.Passive{{Name: "Darkvision", DC: "15"}},
+		Melees:     []structs.Attack{{Name: "dogslicer", DamageBlocks: []structs.DamageBlock{{DamageRoll: "1d6", DamageType: "slashing"}}}},
+		Inventory:  []structs.Item{{Name: "dogslicer", Traits: []string{"agile", "backstabber"}}},
+	}
+	monster.Traits.TraitList = []string{"goblin", "humanoid"}
+
+	rows := &monsterRows{
+		modifierIDs: idPool{101},
+		skillIDs:    idPool{201},
+		actionIDs:   idPool{301, 302},
+		attackIDs:   idPool{401},
+	}
+	rows.add(monster, 7)
+
+	if len(rows.traits) != 2 || rows.traits[0].MonsterID.Int32 != 7 {
+		t.Errorf("Expected two traits for monster 7, got %+v", rows.traits)
+	}
+	if rows.modifiers[0].ID != 101 || rows.exceptions[0].ModifierID.Int32 != 101 || rows.doubles[0].ModifierID.Int32 != 101 {
+		t.Errorf("Expected exceptions and doubles to point at modifier 101")
+	}
+	if rows.skills[0].ID != 201 || rows.skillSpecials[0].SkillID.Int32 != 201 {
+		t.Errorf("Expected skill specials to point at skill 201")
+	}
+	if len(rows.actions) != 2 || rows.actions[1].ActionType.String != "passive" || rows.actions[1].Dc.String != "15" {
+		t.Errorf("Expected the passive after the action, got %+v", rows.actions)
+	}
+	if len(rows.actionTraits) != 1 || rows.actionTraits[0].MonsterActionID.Int32 != 301 {
+		t.Errorf("Expected the action trait on action 301, got %+v", rows.actionTraits)
+	}
+	if rows.damageBlocks[0].AttackID.Int32 != 401 || rows.attacks[0].AttackCategory.String != "melee" {
+		t.Errorf("Expected a melee attack 401 with its damage block")
+	}
+	if len(rows.itemTraits) != 2 || rows.itemTraits[0].ItemID.String != rows.items[0].ID {
+		t.Errorf("Expected item traits to point at the generated item id")
+	}
+	if len(rows.modifierIDs)+len(rows.skillIDs)+len(rows.actionIDs)+len(rows.attackIDs) != 0 {
+		t.Errorf("Expected every reserved id to be used")
+	}
+}
+
+func TestMonsterRowsGiveEachSpellItsOwnRow(t *testing.T) {
+	fireball := structs.Spell{ID: "fireball", Name: "Fireball"}
+	var monster structs.Monster
+	monster.SpellCasting.PreparedSpellCasting = []structs.PreparedSpellCasting{{
+		Slots: []structs.PreparedSlot{{Level: "3", Spell: fireball}, {Level: "3", Spell: fireball}},
+	}}
+	monster.SpellCasting.SpontaneousSpellCasting = []structs.SpontaneousSpellCasting{{
+		SpellList: []structs.Spell{fireball},
+		Slots:     []structs.Slot{{Level: "3", Casts: "2"}},
+	}}
+
+	rows := &monsterRows{preparedIDs: idPool{501}, spontaneousIDs: idPool{601}}
+	rows.add(monster, 7)
+
+	if len(rows.spells) != 3 || len(rows.spellAreas) != 3 || len(rows.durations) != 3 || len(rows.defenses) != 3 {
+		t.Fatalf("Expected a spell row and its details per use, got %d spells", len(rows.spells))
+	}
+	if rows.spells[0].ID == rows.spells[1].ID || rows.spells[0].FoundryID.String != "fireball" || rows.spells[0].MonsterID.Int32 != 7 {
+		t.Errorf("Expected distinct spell rows owned by monster 7, got %+v", rows.spells)
+	}
+	if rows.prepared[0].ID != 501 || rows.preparedSlots[1].PreparedSpellCastingID.Int32 != 501 || rows.preparedSlots[1].SpellID.String != rows.spells[1].ID {
+		t.Errorf("Expected the prepared slots to point at casting 501 and their own spells")
+	}
+	if rows.spellList[0].SpontaneousSpellCastingID.Int32 != 601 || rows.spellList[0].SpellID.String != rows.spells[2].ID || rows.slots[0].Casts.String != "2" {
+		t.Errorf("Expected the spontaneous list and slots to point at casting 601")
+	}
+}
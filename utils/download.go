@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+)
+
+// ErrArchiveChecksum is returned when a download does not match
+// SYNC_ARCHIVE_SHA256.
+var ErrArchiveChecksum = errors.New("downloaded archive does not match the expected checksum")
+
+// DownloadStatusError is a response that is not worth retrying, such as a 401
+// for a bad GH_TOKEN or a 404 for a wrong REPO_URL.
+type DownloadStatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *DownloadStatusError) Error() string {
+	return fmt.Sprintf("failed to download %s: %s", e.URL, e.Status)
+}
+
+// retryableError is a failed attempt that may succeed if tried again. after
+// is how long the server asked us to wait, if it said.
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// archiveDownload fetches url to dest. The bytes go to dest+".part" first,
+// which is renamed over dest only once it is complete and verified, so a
+// failed download never leaves a partial or error page behind as the archive.
+// Attempts after the first resume the part file with a range request.
+type archiveDownload struct {
+	client *http.Client
+	url    string
+	token  string
+	dest   string
+	// sha256 is the expected hex checksum of the archive. When empty the
+	// download is only checked against the size the server reported.
+	sha256 string
+	// attempts is how many requests are made before giving up. The wait
+	// between them doubles from backoff up to maxBackoff, unless the server
+	// sends Retry-After.
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	sleep      func(context.Context, time.Duration) error
+}
+
+// downloadState carries what one attempt learned to the next.
+type downloadState struct {
+	// validator is the ETag or Last-Modified of the archive, sent as
+	// If-Range so a resume never splices two versions together.
+	validator string
+	// total is the full size of the archive, or -1 when the server did not
+	// say.
+	total int64
+}
+
+func newArchiveDownload(cfg config.Config) *archiveDownload {
+	return &archiveDownload{
+		client:     &http.Client{},
+		url:        cfg.REPO_URL,
+		token:      cfg.GH_TOKEN,
+		dest:       cfg.SYNC_ARCHIVE_PATH,
+		sha256:     cfg.SYNC_ARCHIVE_SHA256,
+		attempts:   cfg.SYNC_DOWNLOAD_ATTEMPTS,
+		backoff:    2 * time.Second,
+		maxBackoff: time.Minute,
+		sleep:      sleepContext,
+	}
+}
+
+// run downloads the archive and returns its hex SHA-256.
+func (d *archiveDownload) run(ctx context.Context) (string, error) {
+	partPath := d.dest + ".part"
+	// A part file left by an earlier sync may be of another commit, so
+	// only attempts of this download resume.
+	part, err := os.Create(partPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s %w", partPath, err)
+	}
+	defer func() {
+		part.Close()
+		os.Remove(partPath)
+	}()
+
+	state := downloadState{total: -1}
+	for attempt := 1; ; attempt++ {
+		err := d.fetch(ctx, part, &state)
+		if err == nil {
+			break
+		}
+		var retry *retryableError
+		if !errors.As(err, &retry) || attempt >= d.attempts {
+			return "", err
+		}
+		wait := min(d.backoff<<(attempt-1), d.maxBackoff)
+		if retry.after > 0 {
+			wait = retry.after
+		}
+		logger.Log.Warn("Retrying archive download", "attempt", attempt, "wait", wait, "err", err)
+		if err := d.sleep(ctx, wait); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := part.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, part); err != nil {
+		return "", fmt.Errorf("failed to checksum the download %w", err)
+	}
+	checksum := hex.EncodeToString(h.Sum(nil))
+	if d.sha256 != "" && !strings.EqualFold(checksum, d.sha256) {
+		return "", fmt.Errorf("%w: expected %s, got %s", ErrArchiveChecksum, d.sha256, checksum)
+	}
+	if err := part.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(partPath, d.dest); err != nil {
+		return "", fmt.Errorf("failed to move the download into place %w", err)
+	}
+	return checksum, nil
+}
+
+// fetch makes one request, resuming after whatever part already holds.
+func (d *archiveDownload) fetch(ctx context.Context, part *os.File, state *downloadState) error {
+	offset, err := part.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "MyGoClient/1.0")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if d.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if state.validator != "" {
+			req.Header.Set("If-Range", state.validator)
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		// Either the first response or the server would not resume, in
+		// which case the whole archive is sent again.
+		if err := restartPart(part); err != nil {
+			return err
+		}
+		offset = 0
+		state.total = resp.ContentLength
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			if err := restartPart(part); err != nil {
+				return err
+			}
+			return &retryableError{err: fmt.Errorf("unexpected Content-Range %q resuming at %d", resp.Header.Get("Content-Range"), offset)}
+		}
+		state.total = total
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		if offset == state.total {
+			return nil
+		}
+		if err := restartPart(part); err != nil {
+			return err
+		}
+		return &retryableError{err: fmt.Errorf("server refused to resume at %d", offset)}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return &retryableError{
+			err:   &DownloadStatusError{URL: d.url, StatusCode: resp.StatusCode, Status: resp.Status},
+			after: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	default:
+		return &DownloadStatusError{URL: d.url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		state.validator = etag
+	} else if modified := resp.Header.Get("Last-Modified"); modified != "" {
+		state.validator = modified
+	}
+
+	n, err := io.Copy(part, resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &retryableError{err: fmt.Errorf("download interrupted after %d bytes %w", offset+n, err)}
+	}
+	if state.total >= 0 && offset+n != state.total {
+		if offset+n > state.total {
+			if err := restartPart(part); err != nil {
+				return err
+			}
+		}
+		return &retryableError{err: fmt.Errorf("download ended at %d of %d bytes", offset+n, state.total)}
+	}
+	return nil
+}
+
+// restartPart empties the part file so the next attempt starts over.
+func restartPart(part *os.File) error {
+	if err := part.Truncate(0); err != nil {
+		return err
+	}
+	_, err := part.Seek(0, io.SeekStart)
+	return err
+}
+
+// parseContentRange reads a "bytes start-end/total" header. total is -1 when
+// the server sent "*".
+func parseContentRange(header string) (start int64, total int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	byteRange, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	first, _, found := strings.Cut(byteRange, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if size == "*" {
+		return start, -1, true
+	}
+	total, err = strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
+
+// retryAfter reads a Retry-After header, given in seconds or as an HTTP
+// date. It returns 0 when the header is missing or unreadable.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var downloadBody = strings.Repeat("pf2e archive bytes ", 100)
+
+func downloadChecksum() string {
+	sum := sha256.Sum256([]byte(downloadBody))
+	return hex.EncodeToString(sum[:])
+}
+
+// githubStandIn serves downloadBody like codeload.github.com, with respond
+// deciding each request by its number, starting at 1. It returns the server
+// and the requests it received.
+func githubStandIn(t *testing.T, respond func(n int, w http.ResponseWriter, r *http.Request)) (*httptest.Server, *[]*http.Request) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		n := len(requests)
+		mu.Unlock()
+		respond(n, w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// serveRange answers a Range request for the rest of downloadBody.
+func serveRange(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "-"))
+	if err != nil {
+		http.Error(w, "bad range", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(downloadBody)-1, len(downloadBody)))
+	w.Header().Set("Content-Length", strconv.Itoa(len(downloadBody)-start))
+	w.WriteHeader(http.StatusPartialContent)
+	w.Write([]byte(downloadBody[start:]))
+}
+
+// testDownload downloads from server without waiting between attempts. The
+// waits are recorded instead.
+func testDownload(t *testing.T, server *httptest.Server) (*archiveDownload, *[]time.Duration) {
+	t.Helper()
+	var waits []time.Duration
+	return &archiveDownload{
+		client:     server.Client(),
+		url:        server.URL + "/repos/foundryvtt/pf2e/tarball/master",
+		token:      "secret",
+		dest:       filepath.Join(t.TempDir(), "repo_archive.tar.gz"),
+		attempts:   4,
+		backoff:    time.Second,
+		maxBackoff: 3 * time.Second,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return ctx.Err()
+		},
+	}, &waits
+}
+
+func assertDownloaded(t *testing.T, d *archiveDownload, checksum string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checksum != downloadChecksum() {
+		t.Errorf("Expected checksum %s, got %s", downloadChecksum(), checksum)
+	}
+	data, err := os.ReadFile(d.dest)
+	if err != nil || string(data) != downloadBody {
+		t.Errorf("Expected the archive at %s, got %d bytes (%v)", d.dest, len(data), err)
+	}
+	if _, err := os.Stat(d.dest + ".part"); !os.IsNotExist(err) {
+		t.Errorf("Expected the part file to be gone, got %v", err)
+	}
+}
+
+func assertNotDownloaded(t *testing.T, d *archiveDownload) {
+	t.Helper()
+	for _, path := range []string{d.dest, d.dest + ".part"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected nothing at %s, got %v", path, err)
+		}
+	}
+}
+
+func TestArchiveDownload(t *testing.T) {
+	server, requests := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(downloadBody))
+	})
+	d, _ := testDownload(t, server)
+	d.sha256 = strings.ToUpper(downloadChecksum())
+	checksum, err := d.run(context.Background())
+	assertDownloaded(t, d, checksum, err)
+	if got := (*requests)[0].Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Expected the token to be sent, got %q", got)
+	}
+}
+
+func TestArchiveDownloadDoesNotRetryClientErrors(t *testing.T) {
+	server, requests := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	})
+	d, _ := testDownload(t, server)
+	_, err := d.run(context.Background())
+	var statusErr *DownloadStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected a 401 DownloadStatusError, got %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("Expected a single request, got %d", len(*requests))
+	}
+	assertNotDownloaded(t, d)
+}
+
+func TestArchiveDownloadRetriesWithBackoff(t *testing.T) {
+	server, _ := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(downloadBody))
+		}
+	})
+	d, waits := testDownload(t, server)
+	checksum, err := d.run(context.Background())
+	assertDownloaded(t, d, checksum, err)
+	want := []time.Duration{time.Second, 7 * time.Second, 3 * time.Second}
+	if fmt.Sprint(*waits) != fmt.Sprint(want) {
+		t.Errorf("Expected waits %v, got %v", want, *waits)
+	}
+}
+
+func TestArchiveDownloadGivesUp(t *testing.T) {
+	server, requests := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	d, _ := testDownload(t, server)
+	_, err := d.run(context.Background())
+	var statusErr *DownloadStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected the last status, got %v", err)
+	}
+	if len(*requests) != d.attempts {
+		t.Errorf("Expected %d attempts, got %d", d.attempts, len(*requests))
+	}
+	assertNotDownloaded(t, d)
+}
+
+func TestArchiveDownloadResumes(t *testing.T) {
+	const cut = 500
+	server, requests := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		if n > 1 {
+			serveRange(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(downloadBody)))
+		w.Write([]byte(downloadBody[:cut]))
+		w.(http.Flusher).Flush()
+		// Drop the connection mid-body.
+		panic(http.ErrAbortHandler)
+	})
+	d, _ := testDownload(t, server)
+	checksum, err := d.run(context.Background())
+	assertDownloaded(t, d, checksum, err)
+	resume := (*requests)[1]
+	if resume.Header.Get("Range") != fmt.Sprintf("bytes=%d-", cut) || resume.Header.Get("If-Range") != `"abc123"` {
+		t.Errorf("Expected a conditional range request from %d, got %v", cut, resume.Header)
+	}
+}
+
+func TestArchiveDownloadRestartsWhenRangeIgnored(t *testing.T) {
+	server, _ := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		if n == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(downloadBody)))
+			w.Write([]byte(downloadBody[:100]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		w.Write([]byte(downloadBody))
+	})
+	d, _ := testDownload(t, server)
+	checksum, err := d.run(context.Background())
+	assertDownloaded(t, d, checksum, err)
+}
+
+func TestArchiveDownloadChecksumMismatch(t *testing.T) {
+	server, _ := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(downloadBody))
+	})
+	d, _ := testDownload(t, server)
+	d.sha256 = strings.Repeat("0", 64)
+	if _, err := d.run(context.Background()); !errors.Is(err, ErrArchiveChecksum) {
+		t.Fatalf("Expected ErrArchiveChecksum, got %v", err)
+	}
+	assertNotDownloaded(t, d)
+}
+
+func TestArchiveDownloadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server, _ := githubStandIn(t, func(n int, w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+	d, _ := testDownload(t, server)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := d.run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to end the download, got %v", err)
+	}
+	assertNotDownloaded(t, d)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-5", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.header, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header string
+		start  int64
+		total  int64
+		ok     bool
+	}{
+		{"bytes 500-1899/1900", 500, 1900, true},
+		{"bytes 500-1899/*", 500, -1, true},
+		{"bytes */1900", 0, 0, false},
+		{"500-1899/1900", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.header)
+		if start != tt.start || total != tt.total || ok != tt.ok {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v; want %d, %d, %v", tt.header, start, total, ok, tt.start, tt.total, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+// Creature XP by (creature level - party level), GM Core table 10-2.
+var creatureXpByLevelDifference = map[int]int{
+	-4: 10,
+	-3: 15,
+	-2: 20,
+	-1: 30,
+	0:  40,
+	1:  60,
+	2:  80,
+	3:  120,
+	4:  160,
+}
+
+const (
+	MinCreatureLevelDifference = -4
+	MaxCreatureLevelDifference = 4
+)
+
+var (
+	ErrCreatureOutsideWindow = errors.New("creature level is outside the party level -4 to +4 window")
+	ErrMonsterNotFound       = errors.New("monster not found")
+	ErrInvalidEncounter      = errors.New("invalid encounter")
+)
+
+// MonsterLevelLookup is the subset of writeMonsters.Queries needed to price
+// an encounter.
+type MonsterLevelLookup interface {
+	GetMonsterLevelsByIDs(ctx context.Context, ids []int32) ([]writeMonsters.GetMonsterLevelsByIDsRow, error)
+}
+
+// GetCreatureXp returns the XP a creature of creatureLevel is worth to a party
+// of partyLevel.
+func GetCreatureXp(creatureLevel int, partyLevel int) (int, error) {
+	xp, exists := creatureXpByLevelDifference[creatureLevel-partyLevel]
+	if !exists {
+		return 0, fmt.Errorf("level %d against party level %d: %w", creatureLevel, partyLevel, ErrCreatureOutsideWindow)
+	}
+	return xp, nil
+}
+
+// CalculateEncounterXp looks up the monster of every entry and totals their
+// XP for the party, with levels shifted by any elite or weak template. Each
+// entry is counted Count times, or once when Count is unset. A creature
+// outside the level window is still priced, at the +4 value when it is above
+// the party and at nothing when it is below, and the cost is returned along
+// with an error wrapping ErrCreatureOutsideWindow for each such creature.
+func CalculateEncounterXp(ctx context.Context, lookup MonsterLevelLookup, partyLevel int, entries []structs.EncounterEntry) (structs.EncounterCost, error) {
+	cost := structs.EncounterCost{PartyLevel: partyLevel}
+	if len(entries) == 0 {
+		return cost, nil
+	}
+	var ids []int32
+	seen := make(map[int32]bool)
+	for _, entry := range entries {
+		if !seen[entry.MonsterID] {
+			seen[entry.MonsterID] = true
+			ids = append(ids, entry.MonsterID)
+		}
+	}
+	rows, err := lookup.GetMonsterLevelsByIDs(ctx, ids)
+	if err != nil {
+		return cost, fmt.Errorf("failed to load monster levels %w", err)
+	}
+	byID := make(map[int32]writeMonsters.GetMonsterLevelsByIDsRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+
+	var outside []error
+	for _, entry := range entries {
+		row, found := byID[entry.MonsterID]
+		if !found {
+			return cost, fmt.Errorf("monster ID %d: %w", entry.MonsterID, ErrMonsterNotFound)
+		}
+		level, err := TemplateLevel(int(row.Level.Int32), entry.Template)
+		if err != nil {
+			return cost, fmt.Errorf("%w: monster ID %d %v", ErrInvalidEncounter, entry.MonsterID, err)
+		}
+		xp, err := GetCreatureXp(level, partyLevel)
+		if err != nil {
+			outside = append(outside, fmt.Errorf("%s (ID %d): %w", row.Name, entry.MonsterID, err))
+			if level-partyLevel > MaxCreatureLevelDifference {
+				xp, _ = GetCreatureXp(partyLevel+MaxCreatureLevelDifference, partyLevel)
+			}
+		}
+		creature := structs.EncounterCreature{
+			MonsterID:       entry.MonsterID,
+			Kind:            EntryCreature,
+			Name:            row.Name,
+			Level:           level,
+			Template:        entry.Template,
+			LevelDifference: level - partyLevel,
+			XP:              xp,
+		}
+		for range max(entry.Count, 1) {
+			cost.TotalXP += xp
+			cost.Creatures = append(cost.Creatures, creature)
+		}
+	}
+	return cost, errors.Join(outside...)
+}
+
+// ThreatTiers lists the difficulties understood by GetXpBudget from easiest
+// to hardest.
+var ThreatTiers = []string{"trivial", "low", "moderate", "severe", "extreme"}
+
+const maxCreatureCount = 100
+
+// EncounterLookup is the subset of writeMonsters.Queries needed to price an
+// encounter that may contain both creatures and hazards.
+type EncounterLookup interface {
+	MonsterLevelLookup
+	GetHazardLevelsByIDs(ctx context.Context, ids []int32) ([]writeMonsters.GetHazardLevelsByIDsRow, error)
+}
+
+// Kinds of encounter entry. An empty kind is a creature.
+const (
+	EntryCreature = "creature"
+	EntryHazard   = "hazard"
+)
+
+// EvaluateEncounter prices a proposed encounter with levels taken from the
+// database, shifted by any elite or weak template, and rates it against the
+// party's budget for every threat tier. Hazards are priced with the simple
+// or complex hazard table. Anything outside the level window is reported as
+// a warning rather than failing the whole evaluation: below the party it
+// contributes no XP, above it it is priced at the +4 value and the encounter
+// is rated extreme whatever its total.
+func EvaluateEncounter(ctx context.Context, lookup EncounterLookup, party Party, entries []structs.EncounterEntry) (structs.EncounterEvaluation, error) {
+	partyLevel := party.Level
+	eval := structs.EncounterEvaluation{
+		PartyLevel: partyLevel,
+		PartySize:  party.Size,
+		Warnings:   []structs.EncounterWarning{},
+		Creatures:  []structs.EncounterCreature{},
+	}
+
+	var creatureEntries []structs.EncounterEntry
+	var hazardIDs []int32
+	seenHazards := make(map[int32]bool)
+	total := 0
+	for _, entry := range entries {
+		if entry.Count < 1 {
+			return eval, fmt.Errorf("%w: %s ID %d count must be at least 1", ErrInvalidEncounter, entryKind(entry), entry.MonsterID)
+		}
+		switch entryKind(entry) {
+		case EntryCreature:
+			if _, err := TemplateLevel(0, entry.Template); err != nil {
+				return eval, fmt.Errorf("%w: monster ID %d %v", ErrInvalidEncounter, entry.MonsterID, err)
+			}
+			creatureEntries = append(creatureEntries, entry)
+		case EntryHazard:
+			if entry.Template != "" {
+				return eval, fmt.Errorf("%w: hazard ID %d cannot take a template", ErrInvalidEncounter, entry.MonsterID)
+			}
+			if !seenHazards[entry.MonsterID] {
+				seenHazards[entry.MonsterID] = true
+				hazardIDs = append(hazardIDs, entry.MonsterID)
+			}
+		default:
+			return eval, fmt.Errorf("%w: kind must be %s or %s", ErrInvalidEncounter, EntryCreature, EntryHazard)
+		}
+		total += entry.Count
+	}
+	if total > maxCreatureCount {
+		return eval, fmt.Errorf("%w: %d creatures exceeds the maximum of %d", ErrInvalidEncounter, total, maxCreatureCount)
+	}
+
+	cost, err := CalculateEncounterXp(ctx, lookup, partyLevel, creatureEntries)
+	if err != nil && !errors.Is(err, ErrCreatureOutsideWindow) {
+		return eval, err
+	}
+	hazards := make(map[int32]writeMonsters.GetHazardLevelsByIDsRow, len(hazardIDs))
+	if len(hazardIDs) > 0 {
+		rows, err := lookup.GetHazardLevelsByIDs(ctx, hazardIDs)
+		if err != nil {
+			return eval, fmt.Errorf("failed to load hazard levels %w", err)
+		}
+		for _, row := range rows {
+			hazards[row.ID] = row
+		}
+	}
+
+	// cost.Creatures holds Count copies of each creature entry in order, so
+	// walking it alongside entries keeps creatures and hazards as listed.
+	priced := cost.Creatures
+	aboveWindow := false
+	for _, entry := range entries {
+		var creature structs.EncounterCreature
+		if entryKind(entry) == EntryHazard {
+			row, found := hazards[entry.MonsterID]
+			if !found {
+				return eval, fmt.Errorf("hazard ID %d: %w", entry.MonsterID, ErrMonsterNotFound)
+			}
+			creature = structs.EncounterCreature{MonsterID: entry.MonsterID, Kind: EntryHazard, Name: row.Name, Level: int(row.Level.Int32)}
+			creature.LevelDifference = creature.Level - partyLevel
+			creature.XP, err = GetHazardXp(creature.Level, partyLevel, row.IsComplex)
+			if err != nil && creature.LevelDifference > MaxCreatureLevelDifference {
+				creature.XP, _ = GetHazardXp(partyLevel+MaxCreatureLevelDifference, partyLevel, row.IsComplex)
+			}
+		} else {
+			creature = priced[0]
+			priced = priced[entry.Count:]
+		}
+		if creature.LevelDifference < MinCreatureLevelDifference || creature.LevelDifference > MaxCreatureLevelDifference {
+			eval.Warnings = append(eval.Warnings, levelWindowWarning(creature))
+			aboveWindow = aboveWindow || creature.LevelDifference > MaxCreatureLevelDifference
+		}
+		for range entry.Count {
+			eval.TotalXP += creature.XP
+			eval.Creatures = append(eval.Creatures, creature)
+		}
+	}
+
+	eval.Threat = ThreatTiers[0]
+	for _, tier := range ThreatTiers {
+		budget, err := party.Budget(tier)
+		if err != nil {
+			return eval, err
+		}
+		eval.Budgets = append(eval.Budgets, structs.ThreatBudget{
+			Threat:    tier,
+			Budget:    budget,
+			Remaining: budget - eval.TotalXP,
+		})
+		if eval.TotalXP >= budget {
+			eval.Threat = tier
+		}
+	}
+	if aboveWindow {
+		eval.Threat = ThreatTiers[len(ThreatTiers)-1]
+	}
+	if extreme := eval.Budgets[len(eval.Budgets)-1]; extreme.Remaining < 0 {
+		eval.Warnings = append(eval.Warnings, structs.EncounterWarning{
+			Code:    "exceeds_extreme",
+			Message: fmt.Sprintf("encounter is %d XP over the extreme budget of %d", -extreme.Remaining, extreme.Budget),
+		})
+	}
+	return eval, nil
+}
+
+func entryKind(entry structs.EncounterEntry) string {
+	if entry.Kind == "" {
+		return EntryCreature
+	}
+	return entry.Kind
+}
+
+func levelWindowWarning(creature structs.EncounterCreature) structs.EncounterWarning {
+	difference := creature.LevelDifference
+	if difference > MaxCreatureLevelDifference {
+		return structs.EncounterWarning{
+			Code:      "creature_above_party_level",
+			Message:   fmt.Sprintf("%s is %d levels above the party, beyond the +%d the XP table covers; it is priced at the +%d value and the encounter is rated extreme", creature.Name, difference, MaxCreatureLevelDifference, MaxCreatureLevelDifference),
+			MonsterID: creature.MonsterID,
+		}
+	}
+	return structs.EncounterWarning{
+		Code:      "creature_below_party_level",
+		Message:   fmt.Sprintf("%s is %d levels below the party and is worth no XP", creature.Name, -difference),
+		MonsterID: creature.MonsterID,
+	}
+}
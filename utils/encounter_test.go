@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+type fakeLevelLookup struct {
+	rows    []writeMonsters.GetMonsterLevelsByIDsRow
+	hazards []writeMonsters.GetHazardLevelsByIDsRow
+}
+
+func (f fakeLevelLookup) GetMonsterLevelsByIDs(ctx context.Context, ids []int32) ([]writeMonsters.GetMonsterLevelsByIDsRow, error) {
+	return f.rows, nil
+}
+
+func (f fakeLevelLookup) GetHazardLevelsByIDs(ctx context.Context, ids []int32) ([]writeMonsters.GetHazardLevelsByIDsRow, error) {
+	return f.hazards, nil
+}
+
+func TestGetCreatureXp(t *testing.T) {
+	tests := []struct {
+		creatureLevel int
+		partyLevel    int
+		expected      int
+	}{
+		{1, 5, 10},
+		{2, 5, 15},
+		{3, 5, 20},
+		{4, 5, 30},
+		{5, 5, 40},
+		{6, 5, 60},
+		{7, 5, 80},
+		{8, 5, 120},
+		{9, 5, 160},
+		{-1, 1, 20},
+	}
+	for _, test := range tests {
+		result, err := GetCreatureXp(test.creatureLevel, test.partyLevel)
+		if err != nil {
+			t.Errorf("GetCreatureXp(%d, %d) unexpected error: %v", test.creatureLevel, test.partyLevel, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("GetCreatureXp(%d, %d) = %d; want %d", test.creatureLevel, test.partyLevel, result, test.expected)
+		}
+	}
+}
+
+func TestGetCreatureXpOutsideWindow(t *testing.T) {
+	for _, level := range []int{0, 10, 20} {
+		_, err := GetCreatureXp(level, 5)
+		if !errors.Is(err, ErrCreatureOutsideWindow) {
+			t.Errorf("Expected ErrCreatureOutsideWindow for level %d, got %v", level, err)
+		}
+	}
+}
+
+func TestCalculateEncounterXp(t *testing.T) {
+	lookup := fakeLevelLookup{rows: []writeMonsters.GetMonsterLevelsByIDsRow{
+		{ID: 1, Name: "Goblin Warrior", Level: NewInt4(-1)},
+		{ID: 2, Name: "Goblin Boss", Level: NewInt4(1)},
+	}}
+	cost, err := CalculateEncounterXp(context.Background(), lookup, 1, []structs.EncounterEntry{
+		{MonsterID: 1, Count: 2},
+		{MonsterID: 2},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cost.TotalXP != 80 {
+		t.Errorf("Expected total XP 80, got %d", cost.TotalXP)
+	}
+	if len(cost.Creatures) != 3 {
+		t.Fatalf("Expected 3 creatures, got %d", len(cost.Creatures))
+	}
+	if cost.Creatures[0].LevelDifference != -2 {
+		t.Errorf("Expected level difference -2, got %d", cost.Creatures[0].LevelDifference)
+	}
+
+	_, err = CalculateEncounterXp(context.Background(), lookup, 1, []structs.EncounterEntry{{MonsterID: 3}})
+	if !errors.Is(err, ErrMonsterNotFound) {
+		t.Errorf("Expected ErrMonsterNotFound, got %v", err)
+	}
+	cost, err = CalculateEncounterXp(context.Background(), lookup, 6, []structs.EncounterEntry{{MonsterID: 1}})
+	if !errors.Is(err, ErrCreatureOutsideWindow) {
+		t.Errorf("Expected ErrCreatureOutsideWindow, got %v", err)
+	}
+	if cost.TotalXP != 0 {
+		t.Errorf("Expected a creature below the window to be worth nothing, got %d", cost.TotalXP)
+	}
+	cost, err = CalculateEncounterXp(context.Background(), lookup, -6, []structs.EncounterEntry{{MonsterID: 2, Template: TemplateElite}})
+	if !errors.Is(err, ErrCreatureOutsideWindow) {
+		t.Errorf("Expected ErrCreatureOutsideWindow, got %v", err)
+	}
+	if cost.TotalXP != 160 || cost.Creatures[0].Level != 2 {
+		t.Errorf("Expected an elite creature above the window to be priced at the +4 value, got %+v", cost)
+	}
+}
+
+func TestEvaluateEncounter(t *testing.T) {
+	lookup := fakeLevelLookup{rows: []writeMonsters.GetMonsterLevelsByIDsRow{
+		{ID: 1, Name: "Goblin Warrior", Level: NewInt4(-1)},
+		{ID: 2, Name: "Goblin Boss", Level: NewInt4(1)},
+		{ID: 3, Name: "Young Red Dragon", Level: NewInt4(10)},
+	}}
+	eval, err := EvaluateEncounter(context.Background(), lookup, NewParty(1, 4), []structs.EncounterEntry{
+		{MonsterID: 1, Count: 2},
+		{MonsterID: 2, Count: 1},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if eval.TotalXP != 80 {
+		t.Errorf("Expected total XP 80, got %d", eval.TotalXP)
+	}
+	if eval.Threat != "moderate" {
+		t.Errorf("Expected threat moderate, got %s", eval.Threat)
+	}
+	if len(eval.Budgets) != len(ThreatTiers) {
+		t.Fatalf("Expected %d budgets, got %d", len(ThreatTiers), len(eval.Budgets))
+	}
+	if eval.Budgets[3].Threat != "severe" || eval.Budgets[3].Remaining != 40 {
+		t.Errorf("Expected 40 XP remaining to severe, got %+v", eval.Budgets[3])
+	}
+	if len(eval.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", eval.Warnings)
+	}
+
+	eval, err = EvaluateEncounter(context.Background(), lookup, NewParty(1, 4), []structs.EncounterEntry{{MonsterID: 3, Count: 1}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(eval.Warnings) != 1 || eval.Warnings[0].Code != "creature_above_party_level" {
+		t.Errorf("Expected creature_above_party_level warning, got %v", eval.Warnings)
+	}
+
+	// Six players have an extreme budget of 240, which the dragon's +4
+	// price alone does not reach.
+	eval, err = EvaluateEncounter(context.Background(), lookup, NewParty(3, 6), []structs.EncounterEntry{{MonsterID: 3, Count: 1}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if eval.TotalXP != 160 || eval.Threat != "extreme" {
+		t.Errorf("Expected a creature 7 levels above the party to be priced at 160 XP and rated extreme, got %d XP, %s", eval.TotalXP, eval.Threat)
+	}
+
+	eval, err = EvaluateEncounter(context.Background(), lookup, NewParty(1, 4), []structs.EncounterEntry{
+		{MonsterID: 2, Count: 1},
+		{MonsterID: 1, Count: 2, Template: TemplateWeak},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(eval.Creatures) != 3 || eval.Creatures[0].MonsterID != 2 || eval.Creatures[2].Level != -2 || eval.Creatures[2].Template != TemplateWeak {
+		t.Errorf("Expected the creatures in entry order with templates applied, got %+v", eval.Creatures)
+	}
+
+	_, err = EvaluateEncounter(context.Background(), lookup, NewParty(1, 4), []structs.EncounterEntry{{MonsterID: 1, Count: 0}})
+	if !errors.Is(err, ErrInvalidEncounter) {
+		t.Errorf("Expected ErrInvalidEncounter, got %v", err)
+	}
+}
@@ -2,13 +2,14 @@ package utils
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/Burtcam/encounter-builder-backend/structs"
 	"github.com/tidwall/gjson"
 )
 
-func ItemSwitch(item string, passiveList *[]structs.Passive, SpellCastingBlocks *structs.SpellCasting, FreeActionList *[]structs.FreeAction, ReactionList *[]structs.Reaction, actionList *[]structs.Action, SpellList *[]structs.Spell, MeleeList *[]structs.Attack, RangedList *[]structs.Attack, Inventory *[]structs.Item) error {
+// ItemSwitch sorts an embedded item into the list for its type. Items it
+// cannot place are added to Unclassified with the reason.
+func ItemSwitch(item string, passiveList *[]structs.Passive, SpellCastingBlocks *structs.SpellCasting, FreeActionList *[]structs.FreeAction, ReactionList *[]structs.Reaction, actionList *[]structs.Action, SpellList *[]structs.Spell, MeleeList *[]structs.Attack, RangedList *[]structs.Attack, Inventory *[]structs.Item, Unclassified *[]Unclassified) error {
 	switch gjson.Get(item, "type").String() {
 	case "action":
 		switch gjson.Get(item, "system.actionType.value").String() {
@@ -21,12 +22,8 @@ func ItemSwitch(item string, passiveList *[]structs.Passive, SpellCastingBlocks
 		case "reaction":
 			*ReactionList = append(*ReactionList, ParseReaction(item))
 		default:
-			fmt.Println("Uncategorized!: ", gjson.Get(item, "system.actionType.value"))
-			f, err := os.OpenFile("log.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err != nil {
-				return err
-			}
-			defer f.Close() // Ensure the file is closed when we're done.
+			*Unclassified = append(*Unclassified, unclassifiedItem(item,
+				fmt.Sprintf("unknown action type %q", gjson.Get(item, "system.actionType.value").String())))
 		}
 	case "spellcastingentry":
 		switch gjson.Get(item, "system.prepared.value").String() {
@@ -38,6 +35,9 @@ func ItemSwitch(item string, passiveList *[]structs.Passive, SpellCastingBlocks
 			SpellCastingBlocks.FocusSpellCasting = append(SpellCastingBlocks.FocusSpellCasting, ParseFocusSpellCasting(item))
 		case "innate":
 			SpellCastingBlocks.InnateSpellCasting = append(SpellCastingBlocks.InnateSpellCasting, ParseInnateSpellCasting(item))
+		default:
+			*Unclassified = append(*Unclassified, unclassifiedItem(item,
+				fmt.Sprintf("unknown spellcasting type %q", gjson.Get(item, "system.prepared.value").String())))
 		}
 	case "spell":
 		*SpellList = append(*SpellList, ParseSpell(item))
@@ -80,12 +80,14 @@ func ItemSwitch(item string, passiveList *[]structs.Passive, SpellCastingBlocks
 		default:
 			*MeleeList = append(*MeleeList, ParseWeapon(item))
 		} //switch on the different types and call the ingesters
-
+	default:
+		*Unclassified = append(*Unclassified, unclassifiedItem(item,
+			fmt.Sprintf("unknown item type %q", gjson.Get(item, "type").String())))
 	}
 	return nil
 }
 
-func ParseItems(data gjson.Result) ([]structs.FreeAction, []structs.Action, []structs.Reaction, []structs.Passive, structs.SpellCasting, []structs.Spell, []structs.Attack, []structs.Attack, []structs.Item, error) {
+func ParseItems(data gjson.Result) ([]structs.FreeAction, []structs.Action, []structs.Reaction, []structs.Passive, structs.SpellCasting, []structs.Spell, []structs.Attack, []structs.Attack, []structs.Item, []Unclassified, error) {
 
 	var passiveList []structs.Passive
 	var SpellCastingBlocks structs.SpellCasting
@@ -96,6 +98,7 @@ func ParseItems(data gjson.Result) ([]structs.FreeAction, []structs.Action, []st
 	var MeleeList []structs.Attack
 	var RangedList []structs.Attack
 	var inventory []structs.Item
+	var unclassified []Unclassified
 	arrayJson := data.Array()
 	for i := range len(arrayJson) {
 		err := ItemSwitch(arrayJson[i].String(),
@@ -107,7 +110,8 @@ func ParseItems(data gjson.Result) ([]structs.FreeAction, []structs.Action, []st
 			&SpellMasterList,
 			&MeleeList,
 			&RangedList,
-			&inventory)
+			&inventory,
+			&unclassified)
 
 		if err != nil {
 			return FreeActionList,
@@ -119,6 +123,7 @@ func ParseItems(data gjson.Result) ([]structs.FreeAction, []structs.Action, []st
 				MeleeList,
 				RangedList,
 				inventory,
+				unclassified,
 				err
 		}
 
@@ -132,5 +137,6 @@ func ParseItems(data gjson.Result) ([]structs.FreeAction, []structs.Action, []st
 		MeleeList,
 		RangedList,
 		inventory,
+		unclassified,
 		nil
 }
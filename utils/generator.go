@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+)
+
+// EncounterCandidate is a monster the generator may place in an encounter.
+type EncounterCandidate struct {
+	MonsterID int32
+	Name      string
+	Level     int
+}
+
+// GeneratorOptions controls GenerateEncounters.
+type GeneratorOptions struct {
+	Party        Party
+	Difficulty   string
+	Tolerance    int // XP either side of the budget that still counts as a hit
+	MaxCreatures int
+	Suggestions  int
+	Seed         uint64
+	MustInclude  *EncounterCandidate
+}
+
+const (
+	DefaultGeneratorTolerance    = 10
+	DefaultGeneratorMaxCreatures = 8
+	DefaultGeneratorSuggestions  = 3
+	generatorAttemptsPerResult   = 200
+)
+
+var ErrNoEncounterFound = errors.New("no encounter fits the budget with the given constraints")
+
+// GenerateEncounters fills the XP budget for the party with monsters drawn
+// from candidates. The search is a randomised greedy fill repeated many times;
+// the same seed and candidate list always produce the same suggestions.
+// Suggestions never repeat the same set of creatures, and monsters already
+// used by an earlier suggestion are deprioritised so the results vary.
+func GenerateEncounters(candidates []EncounterCandidate, opts GeneratorOptions) ([]structs.EncounterSuggestion, error) {
+	budget, err := opts.Party.Budget(opts.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Tolerance < 0 {
+		return nil, fmt.Errorf("%w: tolerance cannot be negative", ErrInvalidEncounter)
+	}
+	if opts.MaxCreatures < 1 {
+		opts.MaxCreatures = DefaultGeneratorMaxCreatures
+	}
+	if opts.Suggestions < 1 {
+		opts.Suggestions = DefaultGeneratorSuggestions
+	}
+
+	// Group candidates by the XP they are worth so each pick first chooses a
+	// creature level and then a monster at that level. Picking uniformly over
+	// monsters would favour whichever levels have the most entries.
+	byXp := make(map[int][]EncounterCandidate)
+	for _, c := range candidates {
+		xp, err := GetCreatureXp(c.Level, opts.Party.Level)
+		if err != nil {
+			continue
+		}
+		byXp[xp] = append(byXp[xp], c)
+	}
+	var xpValues []int
+	for xp := range byXp {
+		xpValues = append(xpValues, xp)
+	}
+	slices.Sort(xpValues)
+
+	var required []structs.EncounterCreature
+	if opts.MustInclude != nil {
+		xp, err := GetCreatureXp(opts.MustInclude.Level, opts.Party.Level)
+		if err != nil {
+			return nil, fmt.Errorf("%w: must include %s: %w", ErrInvalidEncounter, opts.MustInclude.Name, err)
+		}
+		required = append(required, newEncounterCreature(*opts.MustInclude, opts.Party.Level, xp))
+	}
+
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+	used := make(map[int32]bool)
+	seen := make(map[string]bool)
+	var suggestions []structs.EncounterSuggestion
+	for attempt := 0; attempt < opts.Suggestions*generatorAttemptsPerResult && len(suggestions) < opts.Suggestions; attempt++ {
+		creatures, total := fillBudget(rng, byXp, xpValues, required, budget, opts, used)
+		if total < budget-opts.Tolerance || total > budget+opts.Tolerance {
+			continue
+		}
+		key := encounterKey(creatures)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for _, c := range creatures {
+			used[c.MonsterID] = true
+		}
+		slices.SortStableFunc(creatures, func(a, b structs.EncounterCreature) int {
+			return b.Level - a.Level
+		})
+		suggestions = append(suggestions, structs.EncounterSuggestion{
+			TotalXP:   total,
+			Budget:    budget,
+			Creatures: creatures,
+		})
+	}
+	if len(suggestions) == 0 {
+		return nil, ErrNoEncounterFound
+	}
+	return suggestions, nil
+}
+
+func fillBudget(rng *rand.Rand, byXp map[int][]EncounterCandidate, xpValues []int, required []structs.EncounterCreature, budget int, opts GeneratorOptions, used map[int32]bool) ([]structs.EncounterCreature, int) {
+	creatures := slices.Clone(required)
+	total := 0
+	for _, c := range creatures {
+		total += c.XP
+	}
+	for len(creatures) < opts.MaxCreatures && total < budget-opts.Tolerance {
+		remaining := budget + opts.Tolerance - total
+		var affordable []int
+		for _, xp := range xpValues {
+			if xp <= remaining {
+				affordable = append(affordable, xp)
+			}
+		}
+		if len(affordable) == 0 {
+			break
+		}
+		xp := affordable[rng.IntN(len(affordable))]
+		pool := byXp[xp]
+		pick := pool[rng.IntN(len(pool))]
+		// Give fresh monsters a second chance before settling for one an
+		// earlier suggestion already used.
+		if used[pick.MonsterID] {
+			pick = pool[rng.IntN(len(pool))]
+		}
+		creatures = append(creatures, newEncounterCreature(pick, opts.Party.Level, xp))
+		total += xp
+	}
+	return creatures, total
+}
+
+func newEncounterCreature(c EncounterCandidate, partyLevel int, xp int) structs.EncounterCreature {
+	return structs.EncounterCreature{
+		MonsterID:       c.MonsterID,
+		Kind:            EntryCreature,
+		Name:            c.Name,
+		Level:           c.Level,
+		LevelDifference: c.Level - partyLevel,
+		XP:              xp,
+	}
+}
+
+// encounterKey identifies an encounter by its creatures regardless of order.
+func encounterKey(creatures []structs.EncounterCreature) string {
+	ids := make([]string, 0, len(creatures))
+	for _, c := range creatures {
+		ids = append(ids, strconv.Itoa(int(c.MonsterID)))
+	}
+	slices.Sort(ids)
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testCandidates() []EncounterCandidate {
+	return []EncounterCandidate{
+		{MonsterID: 1, Name: "Kobold Scout", Level: 1},
+		{MonsterID: 2, Name: "Kobold Warrior", Level: 1},
+		{MonsterID: 3, Name: "Goblin Commando", Level: 2},
+		{MonsterID: 4, Name: "Orc Brute", Level: 3},
+		{MonsterID: 5, Name: "Ogre", Level: 4},
+		{MonsterID: 6, Name: "Owlbear", Level: 5},
+		{MonsterID: 7, Name: "Giant Rat", Level: -1},
+		{MonsterID: 8, Name: "Adult Red Dragon", Level: 14},
+	}
+}
+
+func TestGenerateEncountersWithinTolerance(t *testing.T) {
+	opts := GeneratorOptions{Party: NewParty(3, 4), Difficulty: "moderate", Tolerance: 10, Suggestions: 3, Seed: 42}
+	suggestions, err := GenerateEncounters(testCandidates(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %d", len(suggestions))
+	}
+	seen := make(map[string]bool)
+	for _, s := range suggestions {
+		if s.TotalXP < 70 || s.TotalXP > 90 {
+			t.Errorf("Expected total XP within 80±10, got %d", s.TotalXP)
+		}
+		sum := 0
+		for _, c := range s.Creatures {
+			sum += c.XP
+			if c.MonsterID == 8 {
+				t.Errorf("Creature %s is outside the level window", c.Name)
+			}
+		}
+		if sum != s.TotalXP {
+			t.Errorf("Expected creature XP to sum to %d, got %d", s.TotalXP, sum)
+		}
+		key := encounterKey(s.Creatures)
+		if seen[key] {
+			t.Errorf("Duplicate suggestion %s", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestGenerateEncountersDeterministic(t *testing.T) {
+	opts := GeneratorOptions{Party: NewParty(3, 4), Difficulty: "severe", Tolerance: 10, Seed: 7}
+	first, err := GenerateEncounters(testCandidates(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := GenerateEncounters(testCandidates(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected identical suggestions for the same seed")
+	}
+}
+
+func TestGenerateEncountersMustInclude(t *testing.T) {
+	boss := EncounterCandidate{MonsterID: 6, Name: "Owlbear", Level: 5}
+	opts := GeneratorOptions{Party: NewParty(3, 4), Difficulty: "severe", Tolerance: 0, MaxCreatures: 4, Seed: 1, MustInclude: &boss}
+	suggestions, err := GenerateEncounters(testCandidates(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, s := range suggestions {
+		if s.TotalXP != 120 {
+			t.Errorf("Expected exactly 120 XP, got %d", s.TotalXP)
+		}
+		if len(s.Creatures) > 4 {
+			t.Errorf("Expected at most 4 creatures, got %d", len(s.Creatures))
+		}
+		found := false
+		for _, c := range s.Creatures {
+			if c.MonsterID == boss.MonsterID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected suggestion to include %s", boss.Name)
+		}
+	}
+}
+
+func TestGenerateEncountersNoFit(t *testing.T) {
+	opts := GeneratorOptions{Party: NewParty(10, 4), Difficulty: "moderate", Seed: 1}
+	_, err := GenerateEncounters(testCandidates(), opts)
+	if !errors.Is(err, ErrNoEncounterFound) {
+		t.Errorf("Expected ErrNoEncounterFound, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/tidwall/gjson"
+)
+
+// Simple hazard XP by (hazard level - party level), GM Core table 10-3.
+// Complex hazards are worth the same as a creature of their level.
+var simpleHazardXpByLevelDifference = map[int]int{
+	-4: 2,
+	-3: 3,
+	-2: 4,
+	-1: 6,
+	0:  8,
+	1:  12,
+	2:  16,
+	3:  24,
+	4:  32,
+}
+
+// GetHazardXp returns the XP a hazard of hazardLevel is worth to a party of
+// partyLevel.
+func GetHazardXp(hazardLevel int, partyLevel int, complex bool) (int, error) {
+	if complex {
+		return GetCreatureXp(hazardLevel, partyLevel)
+	}
+	xp, exists := simpleHazardXpByLevelDifference[hazardLevel-partyLevel]
+	if !exists {
+		return 0, fmt.Errorf("level %d against party level %d: %w", hazardLevel, partyLevel, ErrCreatureOutsideWindow)
+	}
+	return xp, nil
+}
+
+// ParseHazard reads a Foundry document of type "hazard".
+func ParseHazard(jsonData string) (structs.Hazard, error) {
+	hazard, _, err := parseHazard(jsonData)
+	return hazard, err
+}
+
+// parseHazard also returns the embedded items that could not be classified.
+func parseHazard(jsonData string) (structs.Hazard, []Unclassified, error) {
+	hazard := structs.Hazard{
+		Name:    gjson.Get(jsonData, "name").String(),
+		Level:   int(gjson.Get(jsonData, "system.details.level.value").Int()),
+		Complex: gjson.Get(jsonData, "system.details.isComplex").Bool(),
+		Traits: structs.Traits{
+			Rarity:    gjson.Get(jsonData, "system.traits.rarity").String(),
+			Size:      gjson.Get(jsonData, "system.traits.size.value").String(),
+			TraitList: ingestJSONList(jsonData, "system.traits.value"),
+		},
+		Stealth: structs.Stealth{
+			Value:  int(gjson.Get(jsonData, "system.attributes.stealth.value").Int()),
+			Detail: StringCleaner(gjson.Get(jsonData, "system.attributes.stealth.details").String()),
+		},
+		Disable: StringCleaner(gjson.Get(jsonData, "system.details.disable").String()),
+		AClass: structs.AC{
+			Value: int(gjson.Get(jsonData, "system.attributes.ac.value").Int()),
+		},
+		Hardness: int(gjson.Get(jsonData, "system.attributes.hardness").Int()),
+		HP: structs.HP{
+			Detail: gjson.Get(jsonData, "system.attributes.hp.details").String(),
+			Value:  int(gjson.Get(jsonData, "system.attributes.hp.max").Int()),
+		},
+		Saves:       ParseSaves(jsonData),
+		Immunities:  extractListOfObjectsValues(jsonData, "system.attributes.immunities"),
+		Description: StringCleaner(gjson.Get(jsonData, "system.details.description").String()),
+		Routine:     StringCleaner(gjson.Get(jsonData, "system.details.routine").String()),
+		Reset:       StringCleaner(gjson.Get(jsonData, "system.details.reset").String()),
+	}
+
+	var unclassified []Unclassified
+	var err error
+	hazard.FreeActions, hazard.Actions, hazard.Reactions, hazard.Passives, _, _, hazard.Melees, hazard.Ranged, _, unclassified, err = ParseItems(gjson.Get(jsonData, "items"))
+	if err != nil {
+		return hazard, unclassified, err
+	}
+	return hazard, unclassified, nil
+}
+
+func PrepHazardParams(hazard structs.Hazard, doc SyncDocument) writeMonsters.UpsertHazardParams {
+	return writeMonsters.UpsertHazardParams{
+		FoundryID:     NewText(doc.FoundryID),
+		PackPath:      NewText(doc.PackPath),
+		ContentHash:   NewText(doc.ContentHash),
+		LastSeenAt:    NewTimestamptz(doc.SeenAt),
+		Name:          hazard.Name,
+		Level:         NewInt4(hazard.Level),
+		IsComplex:     hazard.Complex,
+		TraitsRarity:  NewText(hazard.Traits.Rarity),
+		StealthValue:  NewInt4(hazard.Stealth.Value),
+		StealthDetail: NewText(hazard.Stealth.Detail),
+		Disable:       NewText(hazard.Disable),
+		AcValue:       NewInt4(hazard.AClass.Value),
+		Hardness:      NewInt4(hazard.Hardness),
+		HpValue:       NewInt4(hazard.HP.Value),
+		HpDetail:      NewText(hazard.HP.Detail),
+		SavesFort:     NewInt4(hazard.Saves.Fort),
+		SavesRef:      NewInt4(hazard.Saves.Ref),
+		SavesWill:     NewInt4(hazard.Saves.Will),
+		Description:   NewText(hazard.Description),
+		Routine:       NewText(hazard.Routine),
+		Reset:         NewText(hazard.Reset),
+		Source:        doc.Source,
+	}
+}
+
+func writeHazardActions(ctx context.Context, queries *writeMonsters.Queries, hazard structs.Hazard, id int32) error {
+	type hazardAction struct {
+		actionType string
+		name       string
+		text       string
+		actions    string
+	}
+	var all []hazardAction
+	for _, a := range hazard.Actions {
+		all = append(all, hazardAction{"action", a.Name, a.Text, a.Actions})
+	}
+	for _, a := range hazard.FreeActions {
+		all = append(all, hazardAction{"free_action", a.Name, a.Text, ""})
+	}
+	for _, a := range hazard.Reactions {
+		all = append(all, hazardAction{"reaction", a.Name, a.Text, ""})
+	}
+	for _, a := range hazard.Passives {
+		all = append(all, hazardAction{"passive", a.Name, a.Text, ""})
+	}
+	for _, a := range all {
+		err := queries.InsertHazardAction(ctx, writeMonsters.InsertHazardActionParams{
+			HazardID:   NewInt4(int(id)),
+			ActionType: NewText(a.actionType),
+			Name:       NewText(a.name),
+			Text:       NewText(a.text),
+			Actions:    NewText(a.actions),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write hazard %s %s %w", a.actionType, a.name, err)
+		}
+	}
+	return nil
+}
+
+func writeHazardAttacks(ctx context.Context, queries *writeMonsters.Queries, category string, attacks []structs.Attack, id int32) error {
+	for _, attack := range attacks {
+		attackID, err := queries.InsertHazardAttack(ctx, writeMonsters.InsertHazardAttackParams{
+			HazardID:       NewInt4(int(id)),
+			AttackCategory: NewText(category),
+			Name:           NewText(attack.Name),
+			AttackType:     NewText(attack.Type),
+			ToHitBonus:     NewText(attack.ToHitBonus),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write hazard attack %s %w", attack.Name, err)
+		}
+		for _, block := range attack.DamageBlocks {
+			err = queries.InsertHazardAttackDamageBlock(ctx, writeMonsters.InsertHazardAttackDamageBlockParams{
+				AttackID:   NewInt4(int(attackID)),
+				DamageRoll: NewText(block.DamageRoll),
+				DamageType: NewText(block.DamageType),
+			})
+			if err != nil {
+				return fmt.Errorf("unable to write damage block for hazard attack %s %w", attack.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// HazardRecord is a parsed hazard and the document it was read from.
+type HazardRecord struct {
+	Hazard structs.Hazard
+	Doc    SyncDocument
+}
+
+// WriteHazardToDb stores a hazard and its child rows in one transaction,
+// replacing the rows of an earlier sync of the same document.
+func WriteHazardToDb(hazard structs.Hazard, doc SyncDocument, cfg config.Config) error {
+	ctx := context.Background()
+	tx, err := cfg.DBPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := writeMonsters.New(tx)
+	id, err := queries.UpsertHazard(ctx, PrepHazardParams(hazard, doc))
+	if err != nil {
+		return fmt.Errorf("failed to upsert hazard %w", err)
+	}
+	if err = queries.DeleteHazardChildren(ctx, NewInt4(int(id))); err != nil {
+		return fmt.Errorf("failed to clear previous hazard rows %w", err)
+	}
+	for _, trait := range hazard.Traits.TraitList {
+		err = queries.InsertHazardTraits(ctx, writeMonsters.InsertHazardTraitsParams{HazardID: NewInt4(int(id)), Trait: NewText(trait)})
+		if err != nil {
+			return fmt.Errorf("failed to write hazard traits %w", err)
+		}
+	}
+	for _, immunity := range hazard.Immunities {
+		err = queries.InsertHazardImmunities(ctx, writeMonsters.InsertHazardImmunitiesParams{HazardID: NewInt4(int(id)), Immunity: NewText(immunity)})
+		if err != nil {
+			return fmt.Errorf("failed to write hazard immunities %w", err)
+		}
+	}
+	if err = writeHazardActions(ctx, queries, hazard, id); err != nil {
+		return err
+	}
+	if err = writeHazardAttacks(ctx, queries, "melee", hazard.Melees, id); err != nil {
+		return err
+	}
+	if err = writeHazardAttacks(ctx, queries, "ranged", hazard.Ranged, id); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction close %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("Wrote hazard %s with ID %d", hazard.Name, id))
+	return nil
+}
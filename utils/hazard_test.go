@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+func TestParseHazard(t *testing.T) {
+	jsonData, err := LoadJSON("spear-launcher.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	hazard, err := ParseHazard(jsonData)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hazard.Name != "Spear Launcher" || hazard.Level != 2 || hazard.Complex {
+		t.Errorf("Expected simple level 2 Spear Launcher, got %s level %d complex %v", hazard.Name, hazard.Level, hazard.Complex)
+	}
+	if hazard.Stealth.Value != 20 || hazard.Stealth.Detail != "(trained)" {
+		t.Errorf("Expected stealth 20 (trained), got %d %q", hazard.Stealth.Value, hazard.Stealth.Detail)
+	}
+	if hazard.AClass.Value != 18 || hazard.Hardness != 8 || hazard.HP.Value != 32 || hazard.HP.Detail != "(BT 16)" {
+		t.Errorf("Expected AC 18, hardness 8, HP 32 (BT 16), got %d, %d, %d %s", hazard.AClass.Value, hazard.Hardness, hazard.HP.Value, hazard.HP.Detail)
+	}
+	if hazard.Saves.Fort != 11 || hazard.Saves.Ref != 3 {
+		t.Errorf("Expected Fort 11 Ref 3, got %d %d", hazard.Saves.Fort, hazard.Saves.Ref)
+	}
+	if len(hazard.Immunities) != 3 || hazard.Immunities[0] != "critical-hits" {
+		t.Errorf("Expected 3 immunities starting with critical-hits, got %v", hazard.Immunities)
+	}
+	if hazard.Disable == "" || hazard.Description == "" {
+		t.Errorf("Expected disable and description text")
+	}
+	if len(hazard.Reactions) != 1 || hazard.Reactions[0].Name != "Spear" {
+		t.Errorf("Expected the Spear reaction, got %+v", hazard.Reactions)
+	}
+	if len(hazard.Ranged) != 1 || hazard.Ranged[0].DamageBlocks[0].DamageRoll != "2d6+6" {
+		t.Errorf("Expected a ranged spear doing 2d6+6, got %+v", hazard.Ranged)
+	}
+}
+
+func TestGetHazardXp(t *testing.T) {
+	tests := []struct {
+		hazardLevel int
+		partyLevel  int
+		complex     bool
+		expected    int
+	}{
+		{1, 5, false, 2},
+		{5, 5, false, 8},
+		{9, 5, false, 32},
+		{1, 5, true, 10},
+		{5, 5, true, 40},
+		{9, 5, true, 160},
+	}
+	for _, test := range tests {
+		result, err := GetHazardXp(test.hazardLevel, test.partyLevel, test.complex)
+		if err != nil {
+			t.Errorf("GetHazardXp(%d, %d, %v) unexpected error: %v", test.hazardLevel, test.partyLevel, test.complex, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("GetHazardXp(%d, %d, %v) = %d; want %d", test.hazardLevel, test.partyLevel, test.complex, result, test.expected)
+		}
+	}
+}
+
+func TestEvaluateEncounterWithHazards(t *testing.T) {
+	lookup := fakeLevelLookup{
+		rows: []writeMonsters.GetMonsterLevelsByIDsRow{
+			{ID: 1, Name: "Goblin Warrior", Level: NewInt4(-1)},
+		},
+		hazards: []writeMonsters.GetHazardLevelsByIDsRow{
+			{ID: 1, Name: "Spear Launcher", Level: NewInt4(2), IsComplex: false},
+			{ID: 2, Name: "Town Hall Fire", Level: NewInt4(2), IsComplex: true},
+		},
+	}
+	eval, err := EvaluateEncounter(context.Background(), lookup, NewParty(1, 4), []structs.EncounterEntry{
+		{MonsterID: 1, Count: 1},
+		{MonsterID: 1, Count: 1, Kind: EntryHazard},
+		{MonsterID: 2, Count: 1, Kind: EntryHazard},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 20 for the goblin, 12 for the simple trap and 60 for the complex hazard.
+	if eval.TotalXP != 92 {
+		t.Errorf("Expected total XP 92, got %d", eval.TotalXP)
+	}
+	if eval.Creatures[1].Kind != EntryHazard || eval.Creatures[1].Name != "Spear Launcher" {
+		t.Errorf("Expected the second entry to be the Spear Launcher hazard, got %+v", eval.Creatures[1])
+	}
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+// ImportOptions describes an offline sync from data already on disk.
+type ImportOptions struct {
+	// Path is a .tar.gz archive of the Foundry repository, or a directory it
+	// was extracted into such as files/foundryvtt-pf2e-4cbdaa3.
+	Path string
+	// Packs restricts the import to packs matching any of these glob
+	// patterns, e.g. "pathfinder-bestiary*" or "age-of-ashes-bestiary/book-1-*".
+	// A pattern also matches every pack nested under what it matches. An
+	// empty list imports every pack.
+	Packs []string
+	// Source names the registered data source the data is loaded as;
+	// DefaultSource when empty. The pack list of the source applies as well.
+	Source string
+}
+
+func (o ImportOptions) validate() error {
+	if o.Path == "" {
+		return fmt.Errorf("an import needs a .tar.gz archive or a directory")
+	}
+	for _, pattern := range o.Packs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pack pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ImportSync loads Foundry data from a local archive or directory instead of
+// downloading it. It goes through the same staging, integrity checks, locking
+// and sync_runs recording as KickOffSync. An import restricted to some packs
+// never retires records, since it did not read the other packs.
+func ImportSync(cfg config.Config, opts ImportOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	registry, err := SourcesFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if opts.Source == "" {
+		opts.Source = DefaultSource
+	}
+	source, ok := registry.Lookup(opts.Source)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSource, opts.Source)
+	}
+	ctx := context.Background()
+	lock, runs, err := beginSync(ctx, cfg, []Source{source})
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+	return finishSyncs(ctx, cfg, runs, &opts)
+}
+
+// openImport lists the files of a directory import, or points the sync at an
+// archive to stream. An unfiltered archive whose checksum matches the last
+// successful sync returns ErrUpstreamUnchanged.
+func openImport(opts ImportOptions, last SyncSource, hasLast bool) (syncInput, error) {
+	source := SyncSource{Packs: opts.Packs}
+	info, err := os.Stat(opts.Path)
+	if err != nil {
+		return syncInput{source: source}, fmt.Errorf("failed to open import %w", err)
+	}
+	if info.IsDir() {
+		return listInput(opts.Path, source, opts.Packs), nil
+	}
+	if source.Checksum, err = fileChecksum(opts.Path); err != nil {
+		return syncInput{source: source}, fmt.Errorf("failed to read import %w", err)
+	}
+	if hasLast && len(opts.Packs) == 0 && source.unchangedSince(last, false) {
+		source.Version = last.Version
+		return syncInput{source: source}, ErrUpstreamUnchanged
+	}
+	return syncInput{archive: opts.Path, source: source, complete: true}, nil
+}
+
+// archiveRoot returns the top of the Foundry repository under dir: dir
+// itself if it has a packs folder, otherwise the single directory a GitHub
+// archive unpacks into.
+func archiveRoot(dir string) string {
+	if info, err := os.Stat(filepath.Join(dir, "packs")); err == nil && info.IsDir() {
+		return dir
+	}
+	if version := SourceVersion(dir); version != "" {
+		return filepath.Join(dir, version)
+	}
+	return dir
+}
+
+// filterPacks keeps the files in a packs folder whose pack matches one of
+// patterns, or every pack when patterns is empty. The repository's other
+// JSON files, such as package.json and translations, are never documents.
+func filterPacks(files []string, patterns []string) []string {
+	var kept []string
+	for _, file := range files {
+		if !inPacks(file) {
+			continue
+		}
+		if packWanted(PackPath(file), patterns) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// inPacks reports whether filePath is inside a Foundry packs folder.
+func inPacks(filePath string) bool {
+	for _, part := range strings.Split(path.Dir(filepath.ToSlash(filePath)), "/") {
+		if part == "packs" {
+			return true
+		}
+	}
+	return false
+}
+
+// packWanted reports whether pack passes a filter of patterns, which every
+// pack passes when it is empty.
+func packWanted(pack string, patterns []string) bool {
+	return len(patterns) == 0 || packMatches(pack, patterns)
+}
+
+// packMatches reports whether pack, or a pack it is nested in, matches one
+// of patterns.
+func packMatches(pack string, patterns []string) bool {
+	parts := strings.Split(pack, "/")
+	for i := 1; i <= len(parts); i++ {
+		prefix := strings.Join(parts[:i], "/")
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, prefix); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
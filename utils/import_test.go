@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeArchiveTree lays out a Foundry repository the way a GitHub archive
+// unpacks and returns the directory holding it.
+func writeArchiveTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{
+		"foundryvtt-pf2e-abc123/packs/pathfinder-bestiary/goblin.json",
+		"foundryvtt-pf2e-abc123/packs/age-of-ashes-bestiary/book-1-hellknight-hill/charau-ka.json",
+		"foundryvtt-pf2e-abc123/packs/equipment/sword.json",
+		"foundryvtt-pf2e-abc123/static/lang/en.json",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{"type": "loot"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func writeArchive(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pf2e.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	// Like GitHub's tarballs, start with a global header naming the commit.
+	err = tw.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		Name:       "pax_global_header",
+		PAXRecords: map[string]string{"comment": "abc123"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || file == dir {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, file)
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPackMatches(t *testing.T) {
+	tests := []struct {
+		pack     string
+		patterns []string
+		want     bool
+	}{
+		{"pathfinder-bestiary", []string{"pathfinder-bestiary*"}, true},
+		{"age-of-ashes-bestiary/book-1-hellknight-hill", []string{"age-of-ashes-bestiary"}, true},
+		{"age-of-ashes-bestiary/book-1-hellknight-hill", []string{"age-of-ashes-bestiary/book-2-*"}, false},
+		{"age-of-ashes-bestiary/book-1-hellknight-hill", []string{"*/book-1-*"}, true},
+		{"equipment", []string{"pathfinder-bestiary*", "*-bestiary"}, false},
+	}
+	for _, tt := range tests {
+		if got := packMatches(tt.pack, tt.patterns); got != tt.want {
+			t.Errorf("packMatches(%q, %v) = %v, want %v", tt.pack, tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestImportOptionsValidate(t *testing.T) {
+	if err := (ImportOptions{Path: "pf2e.tar.gz", Packs: []string{"[bestiary"}}).validate(); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+	if err := (ImportOptions{}).validate(); err == nil {
+		t.Error("Expected an error without a path")
+	}
+	if err := (ImportOptions{Path: "pf2e.tar.gz", Packs: []string{"*-bestiary"}}).validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestOpenImportDirectory(t *testing.T) {
+	dir := writeArchiveTree(t)
+	for _, path := range []string{dir, filepath.Join(dir, "foundryvtt-pf2e-abc123")} {
+		input, err := openImport(ImportOptions{Path: path, Packs: []string{"*-bestiary"}}, SyncSource{}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if input.root != filepath.Join(dir, "foundryvtt-pf2e-abc123") || input.source.Version != "foundryvtt-pf2e-abc123" {
+			t.Errorf("%s: unexpected root %s and version %s", path, input.root, input.source.Version)
+		}
+		if len(input.files) != 2 || !input.complete {
+			t.Errorf("%s: expected both bestiary files, got %v", path, input.files)
+		}
+	}
+}
+
+func TestOpenImportArchive(t *testing.T) {
+	archive := writeArchive(t, writeArchiveTree(t))
+
+	input, err := openImport(ImportOptions{Path: archive}, SyncSource{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.archive != archive || len(input.files) != 0 || !input.complete || input.source.Checksum == "" {
+		t.Fatalf("Expected the archive to be streamed, got %+v", input)
+	}
+
+	last := SyncSource{Version: "foundryvtt-pf2e-abc123", Checksum: input.source.Checksum}
+	if _, err := openImport(ImportOptions{Path: archive}, last, true); !errors.Is(err, ErrUpstreamUnchanged) {
+		t.Errorf("Expected the same archive to be skipped, got %v", err)
+	}
+	filtered, err := openImport(ImportOptions{Path: archive, Packs: []string{"equipment"}}, last, true)
+	if err != nil || filtered.archive != archive {
+		t.Errorf("Expected a filtered import of the same archive to run, got %+v %v", filtered, err)
+	}
+}
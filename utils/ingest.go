@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"context"
+	"iter"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+)
+
+// IngestOptions bounds the concurrency of IngestFiles.
+type IngestOptions struct {
+	ParseWorkers int
+	WriteWorkers int
+	// BatchSize is how many monsters each writer commits per transaction.
+	BatchSize int
+	// SourceRoot is the directory the upstream archive unpacked into. When
+	// set, files whose content hash matches the one stored by the last sync
+	// are skipped without being parsed.
+	SourceRoot string
+}
+
+func IngestOptionsFromConfig(cfg config.Config) IngestOptions {
+	return IngestOptions{
+		ParseWorkers: cfg.SYNC_PARSE_WORKERS,
+		WriteWorkers: cfg.SYNC_WRITE_WORKERS,
+		BatchSize:    cfg.SYNC_BATCH_SIZE,
+	}
+}
+
+// sourceDocument is one document a sync reads, either a file on disk or an
+// entry of an archive.
+type sourceDocument struct {
+	path string
+	// key identifies the document across syncs, see sourceFile.
+	key string
+	// read returns the content. Parse workers call it, so files on disk are
+	// read in parallel.
+	read func() ([]byte, error)
+}
+
+// fileDocuments reads files from disk, keyed relative to root.
+func fileDocuments(files []string, root string) iter.Seq[sourceDocument] {
+	return func(yield func(sourceDocument) bool) {
+		for _, file := range files {
+			doc := sourceDocument{
+				path: file,
+				key:  sourceKey(root, file),
+				read: func() ([]byte, error) { return os.ReadFile(file) },
+			}
+			if !yield(doc) {
+				return
+			}
+		}
+	}
+}
+
+type ingestJob struct {
+	doc    sourceDocument
+	result *ingestResult
+}
+
+type ingestWrite struct {
+	result *ingestResult
+	doc    *parsedDocument
+}
+
+// ingestResult is what happened to one document. Each result is only touched
+// by the goroutine that currently owns the document, so results need no lock.
+type ingestResult struct {
+	path         string
+	err          error
+	parsed       bool
+	existing     bool
+	written      bool
+	unclassified []Unclassified
+	source       *sourceFile
+}
+
+// IngestFiles loads files of DefaultSource through a pool of parse workers
+// feeding a pool of writers. The report does not depend on scheduling:
+// failures and unclassified documents are listed in the order of files, and a
+// failed batch is retried one monster at a time so only the files that
+// actually fail are reported. Cancelling ctx stops new work; files that were
+// not loaded are reported with the context error.
+func IngestFiles(ctx context.Context, cfg config.Config, files []string, seenAt time.Time, opts IngestOptions) LoadReport {
+	var hashes map[string]string
+	if opts.SourceRoot != "" {
+		hashes = previousHashes(ctx, cfg, DefaultSource)
+	}
+	return ingest(ctx, cfg, defaultSource(), fileDocuments(files, opts.SourceRoot), seenAt, opts, hashes)
+}
+
+// previousHashes returns the hashes stored by the last syncs of the data
+// source named source, or nil so every document is parsed when they cannot
+// be read.
+func previousHashes(ctx context.Context, cfg config.Config, source string) map[string]string {
+	hashes, err := loadSourceHashes(ctx, cfg, source)
+	if err != nil {
+		logger.Log.Warn("Parsing every file, previous hashes are unavailable", "err", err)
+		return nil
+	}
+	return hashes
+}
+
+// ingest loads docs of src the way IngestFiles loads files. Documents whose
+// key maps to their content hash in hashes are skipped without being parsed.
+func ingest(ctx context.Context, cfg config.Config, src Source, docs iter.Seq[sourceDocument], seenAt time.Time, opts IngestOptions, hashes map[string]string) LoadReport {
+	opts.ParseWorkers = max(opts.ParseWorkers, 1)
+	opts.WriteWorkers = max(opts.WriteWorkers, 1)
+	opts.BatchSize = max(opts.BatchSize, 1)
+
+	jobs := make(chan ingestJob)
+	writes := make(chan ingestWrite, opts.WriteWorkers*opts.BatchSize)
+
+	var parsers sync.WaitGroup
+	for range opts.ParseWorkers {
+		parsers.Add(1)
+		go func() {
+			defer parsers.Done()
+			for job := range jobs {
+				result := job.result
+				if err := ctx.Err(); err != nil {
+					result.err = err
+					continue
+				}
+				data, err := job.doc.read()
+				if err != nil {
+					result.err = err
+					continue
+				}
+				source := sourceFile{key: job.doc.key, doc: NewSyncDocument(job.doc.path, data, seenAt)}
+				source.doc.Source = src.Name
+				result.source = &source
+				if hash, ok := hashes[source.key]; ok && hash == source.doc.ContentHash {
+					continue
+				}
+				doc, err := src.parse(ctx, cfg, source.doc, data)
+				if err != nil {
+					result.err = err
+					continue
+				}
+				if doc == nil {
+					continue
+				}
+				result.unclassified = doc.Unclassified
+				if doc.stored() {
+					result.parsed = true
+					result.existing = doc.Existing
+					writes <- ingestWrite{result: result, doc: doc}
+				}
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for range opts.WriteWorkers {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			var batch []ingestWrite
+			for write := range writes {
+				if err := ctx.Err(); err != nil {
+					write.result.err = err
+					continue
+				}
+				if write.doc.Hazard != nil {
+					write.result.setWritten(write.doc.write(ctx, cfg))
+					continue
+				}
+				batch = append(batch, write)
+				if len(batch) >= opts.BatchSize {
+					writeIngestBatch(ctx, cfg, batch)
+					batch = batch[:0]
+				}
+			}
+			writeIngestBatch(ctx, cfg, batch)
+		}()
+	}
+
+	// Results are listed in the order docs yields them, however long the
+	// workers take.
+	var results []*ingestResult
+	for doc := range docs {
+		result := &ingestResult{path: doc.path}
+		results = append(results, result)
+		select {
+		case jobs <- ingestJob{doc: doc, result: result}:
+		case <-ctx.Done():
+			result.err = ctx.Err()
+		}
+	}
+	close(jobs)
+	parsers.Wait()
+	close(writes)
+	writers.Wait()
+
+	report := LoadReport{Files: len(results)}
+	for _, result := range results {
+		if result.parsed {
+			report.Parsed++
+		}
+		report.Unclassified = append(report.Unclassified, result.unclassified...)
+		if result.err == nil && result.source != nil {
+			report.sources = append(report.sources, *result.source)
+		}
+		switch {
+		case result.err != nil:
+			report.Failures = append(report.Failures, FileFailure{Path: result.path, Err: result.err})
+		case !result.written:
+			report.Skipped++
+		case result.existing:
+			report.Updated++
+		default:
+			report.Inserted++
+		}
+	}
+	report.Failed = len(report.Failures)
+	return report
+}
+
+func (r *ingestResult) setWritten(err error) {
+	r.err = err
+	r.written = err == nil
+}
+
+// writeIngestBatch writes a batch of monsters in one transaction. If it
+// fails, each monster is retried on its own to isolate the file at fault.
+func writeIngestBatch(ctx context.Context, cfg config.Config, batch []ingestWrite) {
+	if len(batch) == 0 {
+		return
+	}
+	records := make([]MonsterRecord, len(batch))
+	for i, write := range batch {
+		records[i] = *write.doc.Monster
+	}
+	if err := WriteMonsterBatch(ctx, cfg, records); err == nil {
+		for _, write := range batch {
+			write.result.written = true
+		}
+		return
+	}
+	for _, write := range batch {
+		write.result.setWritten(WriteMonsterBatch(ctx, cfg, []MonsterRecord{*write.doc.Monster}))
+	}
+}
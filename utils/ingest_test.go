@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+// Documents of a type the sync does not store never reach the database, so
+// they exercise the worker pool without one.
+func ingestFixture(t *testing.T) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var files []string
+	for _, name := range []string{"a.json", "b.json", "missing-1.json", "c.json", "missing-2.json", "d.json"} {
+		path := filepath.Join(dir, name)
+		files = append(files, path)
+		if strings.HasPrefix(name, "missing") {
+			continue
+		}
+		data := []byte(`{"_id": "` + name + `", "type": "loot"}`)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+func TestIngestFilesReportsInFileOrder(t *testing.T) {
+	files := ingestFixture(t)
+	for _, workers := range []int{1, 3, 8} {
+		report := IngestFiles(context.Background(), config.Config{}, files, time.Now(), IngestOptions{
+			ParseWorkers: workers,
+			WriteWorkers: workers,
+			BatchSize:    2,
+		})
+		if report.Files != len(files) || report.Failed != 2 || len(report.Failures) != 2 {
+			t.Fatalf("workers=%d: unexpected report %+v", workers, report)
+		}
+		if report.Failures[0].Path != files[2] || report.Failures[1].Path != files[4] {
+			t.Errorf("workers=%d: expected failures in file order, got %+v", workers, report.Failures)
+		}
+	}
+}
+
+func TestIngestFilesCancelled(t *testing.T) {
+	files := ingestFixture(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := IngestFiles(ctx, config.Config{}, files, time.Now(), IngestOptions{ParseWorkers: 2, WriteWorkers: 2})
+	if report.Failed != len(files) {
+		t.Fatalf("Expected every file to be reported after cancel, got %+v", report)
+	}
+	for _, failure := range report.Failures {
+		if !errors.Is(failure.Err, context.Canceled) {
+			t.Errorf("Unexpected error for %s: %v", failure.Path, failure.Err)
+		}
+	}
+}
+
+func TestIngestFilesSkipsUnchangedFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "packs", "bestiary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, "packs", "bestiary", "goblin.json")
+	data := []byte(`{"_id": "abc", "type": "npc", "name": "Goblin"}`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc := NewSyncDocument(path, data, time.Now())
+	// A matching hash means the npc is never looked up, so no database is
+	// needed.
+	hashes := map[string]string{"packs/bestiary/goblin.json": doc.ContentHash}
+	report := ingest(context.Background(), config.Config{}, defaultSource(), fileDocuments([]string{path}, root), time.Now(), IngestOptions{}, hashes)
+	if report.Skipped != 1 || report.Parsed != 0 || report.Failed != 0 {
+		t.Fatalf("Expected the unchanged file to be skipped, got %+v", report)
+	}
+	if len(report.sources) != 1 || report.sources[0].key != "packs/bestiary/goblin.json" || report.sources[0].doc.FoundryID != "abc" {
+		t.Errorf("Expected the unchanged file to be recorded as read, got %+v", report.sources)
+	}
+}
+
+func TestSourceKey(t *testing.T) {
+	root := filepath.Join("files", "foundryvtt-pf2e-abc123")
+	if got := sourceKey(root, filepath.Join(root, "packs", "a.json")); got != "packs/a.json" {
+		t.Errorf("Expected 'packs/a.json', got '%s'", got)
+	}
+	if got := sourceKey(root, filepath.Join("elsewhere", "a.json")); got != "elsewhere/a.json" {
+		t.Errorf("Expected 'elsewhere/a.json', got '%s'", got)
+	}
+}
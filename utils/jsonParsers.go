@@ -446,11 +446,11 @@ func ParseSenses(jsonData string) []structs.Sense {
 
 func ParseSaves(jsonData string) structs.Saves {
 	save := structs.Saves{
-		Fort:       gjson.Get(jsonData, "system.saves.fortitude.value").String(),
+		Fort:       int(gjson.Get(jsonData, "system.saves.fortitude.value").Int()),
 		FortDetail: gjson.Get(jsonData, "system.saves.fortitude.saveDetail").String(),
-		Will:       gjson.Get(jsonData, "system.saves.will.value").String(),
+		Will:       int(gjson.Get(jsonData, "system.saves.will.value").Int()),
 		WillDetail: gjson.Get(jsonData, "system.saves.will.saveDetail").String(),
-		Ref:        gjson.Get(jsonData, "system.saves.reflex.value").String(),
+		Ref:        int(gjson.Get(jsonData, "system.saves.reflex.value").Int()),
 		RefDetail:  gjson.Get(jsonData, "system.saves.reflex.saveDetail").String(),
 	}
 
@@ -539,17 +539,17 @@ func ParseCoreData(jsonData string) structs.Monster {
 			TraitList: ingestJSONList(jsonData, "system.traits.value"),
 		},
 		Attributes: structs.Attributes{
-			Str: gjson.Get(jsonData, "system.abilities.str.mod").String(),
-			Dex: gjson.Get(jsonData, "system.abilities.dex.mod").String(),
-			Con: gjson.Get(jsonData, "system.abilities.con.mod").String(),
-			Wis: gjson.Get(jsonData, "system.abilities.wis.mod").String(),
-			Int: gjson.Get(jsonData, "system.abilities.int.mod").String(),
-			Cha: gjson.Get(jsonData, "system.abilities.cha.mod").String(),
+			Str: int(gjson.Get(jsonData, "system.abilities.str.mod").Int()),
+			Dex: int(gjson.Get(jsonData, "system.abilities.dex.mod").Int()),
+			Con: int(gjson.Get(jsonData, "system.abilities.con.mod").Int()),
+			Wis: int(gjson.Get(jsonData, "system.abilities.wis.mod").Int()),
+			Int: int(gjson.Get(jsonData, "system.abilities.int.mod").Int()),
+			Cha: int(gjson.Get(jsonData, "system.abilities.cha.mod").Int()),
 		},
-		Level: gjson.Get(jsonData, "system.details.level.value").String(),
+		Level: int(gjson.Get(jsonData, "system.details.level.value").Int()),
 		Saves: ParseSaves(jsonData),
 		AClass: structs.AC{
-			Value:  gjson.Get(jsonData, "system.attributes.ac.value").String(),
+			Value:  int(gjson.Get(jsonData, "system.attributes.ac.value").Int()),
 			Detail: gjson.Get(jsonData, "system.attributes.ac.details").String(),
 		},
 		HP: structs.HP{
@@ -0,0 +1,386 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+)
+
+// dbMonster mirrors the document built by the GetFullMonsterByID query.
+type dbMonster struct {
+	ID               int32    `json:"id"`
+	Name             string   `json:"name"`
+	Level            int      `json:"level"`
+	FocusPoints      int      `json:"focus_points"`
+	TraitsRarity     string   `json:"traits_rarity"`
+	TraitsSize       string   `json:"traits_size"`
+	Traits           []string `json:"traits"`
+	AttrStr          int      `json:"attr_str"`
+	AttrDex          int      `json:"attr_dex"`
+	AttrCon          int      `json:"attr_con"`
+	AttrWis          int      `json:"attr_wis"`
+	AttrInt          int      `json:"attr_int"`
+	AttrCha          int      `json:"attr_cha"`
+	SavesFort        int      `json:"saves_fort"`
+	SavesFortDetail  string   `json:"saves_fort_detail"`
+	SavesRef         int      `json:"saves_ref"`
+	SavesRefDetail   string   `json:"saves_ref_detail"`
+	SavesWill        int      `json:"saves_will"`
+	SavesWillDetail  string   `json:"saves_will_detail"`
+	SavesException   string   `json:"saves_exception"`
+	AcValue          int      `json:"ac_value"`
+	AcDetail         string   `json:"ac_detail"`
+	HpDetail         string   `json:"hp_detail"`
+	HpValue          int      `json:"hp_value"`
+	PerceptionMod    string   `json:"perception_mod"`
+	PerceptionDetail string   `json:"perception_detail"`
+	Immunities       []struct {
+		Immunity string `json:"immunity"`
+	} `json:"immunities"`
+	DamageModifiers []struct {
+		ModifierCategory string   `json:"modifier_category"`
+		Value            int      `json:"value"`
+		DamageType       string   `json:"damage_type"`
+		Exceptions       []string `json:"exceptions"`
+		Doubles          []string `json:"doubles"`
+	} `json:"damage_modifiers"`
+	Languages []string `json:"languages"`
+	Senses    []struct {
+		Name   string `json:"name"`
+		Range  string `json:"range"`
+		Acuity string `json:"acuity"`
+		Detail string `json:"detail"`
+	} `json:"senses"`
+	Skills []struct {
+		Name     string `json:"name"`
+		Value    int    `json:"value"`
+		Specials []struct {
+			Value      int      `json:"value"`
+			Label      string   `json:"label"`
+			Predicates []string `json:"predicates"`
+		} `json:"specials"`
+	} `json:"skills"`
+	Movements []struct {
+		MovementType string `json:"movement_type"`
+		Speed        string `json:"speed"`
+		Notes        string `json:"notes"`
+	} `json:"movements"`
+	Actions []struct {
+		ActionType string   `json:"action_type"`
+		Name       string   `json:"name"`
+		Text       string   `json:"text"`
+		Actions    string   `json:"actions"`
+		Category   string   `json:"category"`
+		Rarity     string   `json:"rarity"`
+		DC         string   `json:"dc"`
+		Traits     []string `json:"traits"`
+	} `json:"actions"`
+	Attacks []struct {
+		AttackCategory      string   `json:"attack_category"`
+		Name                string   `json:"name"`
+		AttackType          string   `json:"attack_type"`
+		ToHitBonus          string   `json:"to_hit_bonus"`
+		EffectsCustomString string   `json:"effects_custom_string"`
+		EffectsValues       []string `json:"effects_values"`
+		DamageBlocks        []struct {
+			DamageRoll string `json:"damage_roll"`
+			DamageType string `json:"damage_type"`
+		} `json:"damage_blocks"`
+	} `json:"attacks"`
+	FocusSpellCasting []struct {
+		DC             int      `json:"dc"`
+		Mod            string   `json:"mod"`
+		Tradition      string   `json:"tradition"`
+		SpellcastingID string   `json:"spellcasting_id"`
+		Name           string   `json:"name"`
+		Description    string   `json:"description"`
+		CastLevel      string   `json:"cast_level"`
+		Spells         []string `json:"spells"`
+	} `json:"focus_spell_casting"`
+	InnateSpellCasting []struct {
+		DC             int    `json:"dc"`
+		Tradition      string `json:"tradition"`
+		Mod            string `json:"mod"`
+		SpellcastingID string `json:"spellcasting_id"`
+		Name           string `json:"name"`
+		Description    string `json:"description"`
+		Uses           []struct {
+			SpellID string `json:"spell_id"`
+			Level   int    `json:"level"`
+			Uses    string `json:"uses"`
+		} `json:"uses"`
+	} `json:"innate_spell_casting"`
+	PreparedSpellCasting []struct {
+		DC             int    `json:"dc"`
+		Tradition      string `json:"tradition"`
+		Mod            string `json:"mod"`
+		SpellcastingID string `json:"spellcasting_id"`
+		Description    string `json:"description"`
+		Slots          []struct {
+			Level   string `json:"level"`
+			SpellID string `json:"spell_id"`
+		} `json:"slots"`
+	} `json:"prepared_spell_casting"`
+	SpontaneousSpellCasting []struct {
+		DC               int    `json:"dc"`
+		IDString         string `json:"id_string"`
+		Tradition        string `json:"tradition"`
+		Mod              string `json:"mod"`
+		SpontaneousSlots []struct {
+			Level string `json:"level"`
+			Casts string `json:"casts"`
+		} `json:"spontaneous_slots"`
+		SpontaneousSpellList []struct {
+			SpellID string `json:"spell_id"`
+		} `json:"spontaneous_spell_list"`
+	} `json:"spontaneous_spell_casting"`
+	Items []struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Category    string   `json:"category"`
+		Description string   `json:"description"`
+		Level       string   `json:"level"`
+		Type        string   `json:"type"`
+		Rarity      string   `json:"rarity"`
+		Size        string   `json:"size"`
+		Range       string   `json:"range"`
+		Reload      string   `json:"reload"`
+		Bulk        string   `json:"bulk"`
+		Quantity    string   `json:"quantity"`
+		PricePer    int      `json:"price_per"`
+		PriceCp     int      `json:"price_cp"`
+		PriceSp     int      `json:"price_sp"`
+		PriceGp     int      `json:"price_gp"`
+		PricePp     int      `json:"price_pp"`
+		Traits      []string `json:"traits"`
+	} `json:"items"`
+}
+
+// MonsterFromDB converts a GetFullMonsterByID document into a structs.Monster.
+// Spells are referenced by ID only.
+func MonsterFromDB(data []byte) (structs.Monster, error) {
+	var row dbMonster
+	if err := json.Unmarshal(data, &row); err != nil {
+		return structs.Monster{}, fmt.Errorf("failed to decode monster document %w", err)
+	}
+	monster := structs.Monster{
+		Name: row.Name,
+		Traits: structs.Traits{
+			Rarity:    row.TraitsRarity,
+			Size:      row.TraitsSize,
+			TraitList: row.Traits,
+		},
+		Attributes: structs.Attributes{
+			Str: row.AttrStr,
+			Dex: row.AttrDex,
+			Con: row.AttrCon,
+			Wis: row.AttrWis,
+			Int: row.AttrInt,
+			Cha: row.AttrCha,
+		},
+		Level: row.Level,
+		Saves: structs.Saves{
+			Fort:       row.SavesFort,
+			FortDetail: row.SavesFortDetail,
+			Ref:        row.SavesRef,
+			RefDetail:  row.SavesRefDetail,
+			Will:       row.SavesWill,
+			WillDetail: row.SavesWillDetail,
+			Exception:  row.SavesException,
+		},
+		AClass:      structs.AC{Value: row.AcValue, Detail: row.AcDetail},
+		HP:          structs.HP{Value: row.HpValue, Detail: row.HpDetail},
+		Perception:  structs.Perception{Mod: row.PerceptionMod, Detail: row.PerceptionDetail},
+		Languages:   row.Languages,
+		FocusPoints: row.FocusPoints,
+	}
+	for _, i := range row.Immunities {
+		monster.Immunities = append(monster.Immunities, i.Immunity)
+	}
+	for _, md := range row.DamageModifiers {
+		block := structs.DamageModifierBlock{
+			Value:      md.Value,
+			Type:       md.DamageType,
+			Exceptions: md.Exceptions,
+			Double:     md.Doubles,
+		}
+		if md.ModifierCategory == "weakness" {
+			monster.Weaknesses = append(monster.Weaknesses, block)
+		} else {
+			monster.Resistances = append(monster.Resistances, block)
+		}
+	}
+	for _, s := range row.Senses {
+		monster.Senses = append(monster.Senses, structs.Sense{Name: s.Name, Range: s.Range, Acuity: s.Acuity, Detail: s.Detail})
+	}
+	for _, s := range row.Skills {
+		skill := structs.Skill{Name: s.Name, Value: s.Value}
+		for _, sp := range s.Specials {
+			skill.Specials = append(skill.Specials, structs.SkillSpecial{Value: sp.Value, Label: sp.Label, Predicates: sp.Predicates})
+		}
+		monster.Skills = append(monster.Skills, skill)
+	}
+	for _, m := range row.Movements {
+		monster.Movements = append(monster.Movements, structs.Movement{Type: m.MovementType, Speed: m.Speed, Notes: m.Notes})
+	}
+	for _, a := range row.Actions {
+		switch a.ActionType {
+		case "action":
+			monster.Actions = append(monster.Actions, structs.Action{Name: a.Name, Text: a.Text, Traits: a.Traits, Actions: a.Actions, Category: a.Category, Rarity: a.Rarity})
+		case "free_action":
+			monster.FreeActions = append(monster.FreeActions, structs.FreeAction{Name: a.Name, Text: a.Text, Traits: a.Traits, Category: a.Category, Rarity: a.Rarity})
+		case "reaction":
+			monster.Reactions = append(monster.Reactions, structs.Reaction{Name: a.Name, Text: a.Text, Traits: a.Traits, Category: a.Category, Rarity: a.Rarity})
+		case "passive":
+			monster.Passives = append(monster.Passives, structs.Passive{Name: a.Name, Text: a.Text, Traits: a.Traits, DC: a.DC, Category: a.Category, Rarity: a.Rarity})
+		}
+	}
+	for _, a := range row.Attacks {
+		attack := structs.Attack{
+			Name:       a.Name,
+			Type:       a.AttackType,
+			ToHitBonus: a.ToHitBonus,
+			Effects:    structs.DamageEffect{CustomString: a.EffectsCustomString, Value: a.EffectsValues},
+		}
+		for _, d := range a.DamageBlocks {
+			attack.DamageBlocks = append(attack.DamageBlocks, structs.DamageBlock{DamageRoll: d.DamageRoll, DamageType: d.DamageType})
+		}
+		if a.AttackCategory == "ranged" {
+			monster.Ranged = append(monster.Ranged, attack)
+		} else {
+			monster.Melees = append(monster.Melees, attack)
+		}
+	}
+	for _, f := range row.FocusSpellCasting {
+		block := structs.FocusSpellCasting{DC: f.DC, Mod: f.Mod, Tradition: f.Tradition, ID: f.SpellcastingID, Name: f.Name, Description: f.Description, CastLevel: f.CastLevel}
+		for _, id := range f.Spells {
+			block.FocusSpellList = append(block.FocusSpellList, structs.Spell{ID: id})
+		}
+		monster.SpellCasting.FocusSpellCasting = append(monster.SpellCasting.FocusSpellCasting, block)
+	}
+	for _, in := range row.InnateSpellCasting {
+		block := structs.InnateSpellCasting{DC: in.DC, Tradition: in.Tradition, Mod: in.Mod, ID: in.SpellcastingID, Name: in.Name, Description: in.Description}
+		for _, u := range in.Uses {
+			block.SpellUses = append(block.SpellUses, structs.SpellUse{Spell: structs.Spell{ID: u.SpellID}, Level: u.Level, Uses: u.Uses})
+		}
+		monster.SpellCasting.InnateSpellCasting = append(monster.SpellCasting.InnateSpellCasting, block)
+	}
+	for _, p := range row.PreparedSpellCasting {
+		block := structs.PreparedSpellCasting{DC: p.DC, Tradition: p.Tradition, Mod: p.Mod, ID: p.SpellcastingID, Description: p.Description}
+		for _, slot := range p.Slots {
+			block.Slots = append(block.Slots, structs.PreparedSlot{Level: slot.Level, SpellID: slot.SpellID, Spell: structs.Spell{ID: slot.SpellID}})
+		}
+		monster.SpellCasting.PreparedSpellCasting = append(monster.SpellCasting.PreparedSpellCasting, block)
+	}
+	for _, sp := range row.SpontaneousSpellCasting {
+		block := structs.SpontaneousSpellCasting{DC: sp.DC, ID: sp.IDString, Tradition: sp.Tradition, Mod: sp.Mod}
+		for _, slot := range sp.SpontaneousSlots {
+			block.Slots = append(block.Slots, structs.Slot{Level: slot.Level, Casts: slot.Casts})
+		}
+		for _, s := range sp.SpontaneousSpellList {
+			block.SpellList = append(block.SpellList, structs.Spell{ID: s.SpellID})
+		}
+		monster.SpellCasting.SpontaneousSpellCasting = append(monster.SpellCasting.SpontaneousSpellCasting, block)
+	}
+	for _, i := range row.Items {
+		monster.Inventory = append(monster.Inventory, structs.Item{
+			Name:        i.Name,
+			ID:          i.ID,
+			Category:    i.Category,
+			Description: i.Description,
+			Level:       i.Level,
+			Price:       structs.PriceBlock{Per: i.PricePer, CP: i.PriceCp, SP: i.PriceSp, GP: i.PriceGp, PP: i.PricePp},
+			Type:        i.Type,
+			Traits:      i.Traits,
+			Rarity:      i.Rarity,
+			Size:        i.Size,
+			Range:       i.Range,
+			Reload:      i.Reload,
+			Bulk:        i.Bulk,
+			Quantity:    i.Quantity,
+		})
+	}
+	return monster, nil
+}
+
+// TemplateMonsterDocument applies the elite or weak template to a
+// GetFullMonsterByID document and returns it in the same shape, so a
+// templated monster reads like any other. The adjusted values come from
+// ApplyTemplate. The template and the change in level are added as
+// "template" and "level_adjustment".
+func TemplateMonsterDocument(data []byte, template string) ([]byte, error) {
+	monster, err := MonsterFromDB(data)
+	if err != nil {
+		return nil, err
+	}
+	adjusted, err := ApplyTemplate(monster, template)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode monster document %w", err)
+	}
+
+	doc["level"] = adjusted.Level
+	doc["hp_value"] = adjusted.HP.Value
+	doc["ac_value"] = adjusted.AClass.Value
+	doc["saves_fort"] = adjusted.Saves.Fort
+	doc["saves_ref"] = adjusted.Saves.Ref
+	doc["saves_will"] = adjusted.Saves.Will
+	doc["perception_mod"] = adjusted.Perception.Mod
+	for i, skill := range documentList(doc, "skills") {
+		skill["value"] = adjusted.Skills[i].Value
+		for j, special := range documentList(skill, "specials") {
+			special["value"] = adjusted.Skills[i].Specials[j].Value
+		}
+	}
+	// MonsterFromDB splits the attacks by category in document order.
+	melees, ranged := adjusted.Melees, adjusted.Ranged
+	for _, attack := range documentList(doc, "attacks") {
+		var adjustedAttack structs.Attack
+		if attack["attack_category"] == "ranged" {
+			adjustedAttack, ranged = ranged[0], ranged[1:]
+		} else {
+			adjustedAttack, melees = melees[0], melees[1:]
+		}
+		attack["to_hit_bonus"] = adjustedAttack.ToHitBonus
+		for j, block := range documentList(attack, "damage_blocks") {
+			block["damage_roll"] = adjustedAttack.DamageBlocks[j].DamageRoll
+		}
+	}
+	casting := adjusted.SpellCasting
+	for i, block := range documentList(doc, "focus_spell_casting") {
+		block["dc"], block["mod"] = casting.FocusSpellCasting[i].DC, casting.FocusSpellCasting[i].Mod
+	}
+	for i, block := range documentList(doc, "innate_spell_casting") {
+		block["dc"], block["mod"] = casting.InnateSpellCasting[i].DC, casting.InnateSpellCasting[i].Mod
+	}
+	for i, block := range documentList(doc, "prepared_spell_casting") {
+		block["dc"], block["mod"] = casting.PreparedSpellCasting[i].DC, casting.PreparedSpellCasting[i].Mod
+	}
+	for i, block := range documentList(doc, "spontaneous_spell_casting") {
+		block["dc"], block["mod"] = casting.SpontaneousSpellCasting[i].DC, casting.SpontaneousSpellCasting[i].Mod
+	}
+
+	doc["template"] = template
+	doc["level_adjustment"] = adjusted.Level - monster.Level
+	return json.Marshal(doc)
+}
+
+// documentList returns the objects of the array under key, or nil when the
+// query aggregated no rows into it.
+func documentList(doc map[string]any, key string) []map[string]any {
+	items, _ := doc[key].([]any)
+	list := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if object, ok := item.(map[string]any); ok {
+			list = append(list, object)
+		}
+	}
+	return list
+}
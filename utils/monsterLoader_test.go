@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMonsterFromDB(t *testing.T) {
+	data := []byte(`{
+		"id": 7, "name": "Goblin Warrior", "level": -1, "traits_rarity": "common",
+		"traits": ["goblin", "humanoid"], "ac_value": 16, "hp_value": 6, "perception_mod": "2",
+		"immunities": null,
+		"damage_modifiers": [{"modifier_category": "weakness", "value": 5, "damage_type": "cold"}],
+		"skills": [{"name": "stealth", "value": 5, "specials": null}],
+		"actions": [{"action_type": "reaction", "name": "Goblin Scuttle"}],
+		"attacks": [{"attack_category": "melee", "name": "Dogslicer", "to_hit_bonus": "8",
+			"damage_blocks": [{"damage_roll": "1d6", "damage_type": "slashing"}]}],
+		"innate_spell_casting": [{"dc": 15, "mod": "7", "uses": [{"spell_id": "abc", "level": 1, "uses": "1"}]}]
+	}`)
+	monster, err := MonsterFromDB(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if monster.Name != "Goblin Warrior" || monster.Level != -1 {
+		t.Errorf("Expected Goblin Warrior level -1, got %s level %d", monster.Name, monster.Level)
+	}
+	if len(monster.Traits.TraitList) != 2 || monster.AClass.Value != 16 {
+		t.Errorf("Expected 2 traits and AC 16, got %v and %d", monster.Traits.TraitList, monster.AClass.Value)
+	}
+	if len(monster.Weaknesses) != 1 || len(monster.Resistances) != 0 {
+		t.Errorf("Expected 1 weakness and no resistances, got %d and %d", len(monster.Weaknesses), len(monster.Resistances))
+	}
+	if len(monster.Reactions) != 1 || len(monster.Actions) != 0 {
+		t.Errorf("Expected 1 reaction and no actions, got %d and %d", len(monster.Reactions), len(monster.Actions))
+	}
+	if len(monster.Melees) != 1 || monster.Melees[0].DamageBlocks[0].DamageRoll != "1d6" {
+		t.Errorf("Expected Dogslicer with 1d6 damage, got %+v", monster.Melees)
+	}
+	innate := monster.SpellCasting.InnateSpellCasting
+	if len(innate) != 1 || innate[0].DC != 15 || innate[0].SpellUses[0].Spell.ID != "abc" {
+		t.Errorf("Expected innate casting DC 15 with spell abc, got %+v", innate)
+	}
+}
+
+func TestTemplateMonsterDocument(t *testing.T) {
+	data := []byte(`{
+		"id": 7, "name": "Goblin Archer", "level": 1, "source": "pf2e", "foundry_id": "x1",
+		"ac_value": 17, "hp_value": 20, "saves_fort": 5, "saves_ref": 9, "saves_will": 4, "perception_mod": "6",
+		"skills": [{"name": "stealth", "value": 7, "specials": [{"value": 9, "label": "in forests"}]}],
+		"attacks": [
+			{"attack_category": "ranged", "name": "Shortbow", "to_hit_bonus": "9", "damage_blocks": [{"damage_roll": "1d6", "damage_type": "piercing"}]},
+			{"attack_category": "melee", "name": "Dogslicer", "to_hit_bonus": "7", "damage_blocks": [{"damage_roll": "1d6+2", "damage_type": "slashing"}]}
+		],
+		"prepared_spell_casting": [{"dc": 17, "mod": "9", "slots": null}],
+		"items": null
+	}`)
+	adjusted, err := TemplateMonsterDocument(data, TemplateElite)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var original, doc map[string]any
+	if err := json.Unmarshal(data, &original); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(adjusted, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for key := range original {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+	if doc["template"] != TemplateElite || doc["level_adjustment"] != 1.0 || doc["level"] != 2.0 {
+		t.Errorf("Expected elite level 2 adjusted by 1, got %v %v %v", doc["template"], doc["level_adjustment"], doc["level"])
+	}
+	if doc["ac_value"] != 19.0 || doc["hp_value"] != 30.0 || doc["perception_mod"] != "8" || doc["source"] != "pf2e" {
+		t.Errorf("Unexpected stat block %v", doc)
+	}
+	skill := doc["skills"].([]any)[0].(map[string]any)
+	if skill["value"] != 9.0 || skill["specials"].([]any)[0].(map[string]any)["value"] != 11.0 {
+		t.Errorf("Expected adjusted skills, got %v", skill)
+	}
+	attacks := doc["attacks"].([]any)
+	shortbow, dogslicer := attacks[0].(map[string]any), attacks[1].(map[string]any)
+	if shortbow["to_hit_bonus"] != "11" || dogslicer["to_hit_bonus"] != "9" {
+		t.Errorf("Expected each attack adjusted in place, got %v and %v", shortbow, dogslicer)
+	}
+	if roll := dogslicer["damage_blocks"].([]any)[0].(map[string]any)["damage_roll"]; roll != "1d6+4" {
+		t.Errorf("Expected damage 1d6+4, got %v", roll)
+	}
+	if dc := doc["prepared_spell_casting"].([]any)[0].(map[string]any)["dc"]; dc != 19.0 {
+		t.Errorf("Expected spell DC 19, got %v", dc)
+	}
+	if doc["items"] != nil {
+		t.Errorf("Expected empty aggregates to stay null, got %v", doc["items"])
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+// SectionError reports which part of a monster's stat block failed to write
+// and the file it came from.
+type SectionError struct {
+	Section string
+	Monster string
+	Path    string
+	Err     error
+}
+
+func (e *SectionError) Error() string {
+	return fmt.Sprintf("failed to write %s for monster %s from %s: %v", e.Section, e.Monster, e.Path, e.Err)
+}
+
+func (e *SectionError) Unwrap() error {
+	return e.Err
+}
+
+// MonsterRecord is a parsed monster and the document it was read from.
+type MonsterRecord struct {
+	Monster structs.Monster
+	Doc     SyncDocument
+}
+
+// upsertMonster stores the monster row itself and clears the child rows
+// from its last sync, returning its id.
+func upsertMonster(ctx context.Context, queries *writeMonsters.Queries, record MonsterRecord) (int32, error) {
+	id, err := queries.UpsertMonster(ctx, PrepMonsterParams(record.Monster, record.Doc))
+	if err != nil {
+		return 0, record.sectionError("stat block", err)
+	}
+	err = queries.DeleteMonsterChildren(ctx, NewInt4(int(id)))
+	if err != nil {
+		return 0, record.sectionError("previous rows", err)
+	}
+	return id, nil
+}
+
+func (r MonsterRecord) sectionError(section string, err error) error {
+	return &SectionError{Section: section, Monster: r.Monster.Name, Path: r.Doc.Path, Err: err}
+}
+
+// batchSectionError reports a failure that cannot be pinned on one monster,
+// such as a bulk load of a table shared by the whole batch.
+func batchSectionError(records []MonsterRecord, section string, err error) error {
+	if len(records) == 1 {
+		return records[0].sectionError(section, err)
+	}
+	return &SectionError{
+		Section: section,
+		Monster: fmt.Sprintf("batch of %d starting with %s", len(records), records[0].Monster.Name),
+		Path:    records[0].Doc.Path,
+		Err:     err,
+	}
+}
+
+// WriteMonsterToDb stores a monster and its child rows in one transaction.
+func WriteMonsterToDb(monster structs.Monster, doc SyncDocument, cfg config.Config) error {
+	return WriteMonsterBatch(context.Background(), cfg, []MonsterRecord{{Monster: monster, Doc: doc}})
+}
+
+// WriteMonsterBatch writes every record in a single transaction. Child rows
+// for the whole batch are bulk loaded one table at a time. If anything fails
+// the whole batch is rolled back and a *SectionError is returned.
+func WriteMonsterBatch(ctx context.Context, cfg config.Config, records []MonsterRecord) error {
+	tx, err := cfg.DBPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := writeMonsters.New(tx)
+	ids := make([]int32, len(records))
+	for i, record := range records {
+		if ids[i], err = upsertMonster(ctx, queries, record); err != nil {
+			return err
+		}
+	}
+	rows, err := newMonsterRows(ctx, queries, records, ids)
+	if err != nil {
+		return batchSectionError(records, "id reservation", err)
+	}
+	if table, err := rows.load(ctx, queries); err != nil {
+		return batchSectionError(records, table, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction close %w", err)
+	}
+	for i, record := range records {
+		logger.Log.Info(fmt.Sprintf("Wrote monster %s with ID %d", record.Monster.Name, ids[i]))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSectionError(t *testing.T) {
+	cause := errors.New("duplicate key")
+	var err error = &SectionError{
+		Section: "skills",
+		Monster: "Goblin Warrior",
+		Path:    "packs/pathfinder-bestiary/goblin-warrior.json",
+		Err:     cause,
+	}
+	for _, want := range []string{"skills", "Goblin Warrior", "packs/pathfinder-bestiary/goblin-warrior.json", "duplicate key"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q to mention %q", err.Error(), want)
+		}
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected SectionError to unwrap to its cause")
+	}
+	var sectionErr *SectionError
+	if !errors.As(err, &sectionErr) || sectionErr.Section != "skills" {
+		t.Errorf("Expected errors.As to find the section")
+	}
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+)
+
+// Party is the group an encounter is built for. Levels is only set for
+// parties whose characters are not all the same level.
+type Party struct {
+	Level  int
+	Size   int
+	Levels []int
+}
+
+// NewParty describes a party whose characters all share one level.
+func NewParty(level int, size int) Party {
+	return Party{Level: level, Size: size}
+}
+
+// NewMixedParty describes a party from the level of each character, including
+// sidekicks and companion NPCs that fight alongside them.
+func NewMixedParty(levels []int) (Party, error) {
+	level, err := GetMixedPartyLevel(levels)
+	if err != nil {
+		return Party{}, err
+	}
+	party := Party{Level: level, Size: len(levels)}
+	for _, l := range levels {
+		if l != level {
+			party.Levels = slices.Clone(levels)
+			break
+		}
+	}
+	return party, nil
+}
+
+// GetMixedPartyLevel picks the party level for characters of differing
+// levels. Following the GM Core guidance, the level shared by a majority of
+// the group wins; without a majority the average is used, rounded to the
+// nearest level.
+func GetMixedPartyLevel(levels []int) (int, error) {
+	if len(levels) == 0 {
+		return 0, ErrInvalidPartySize
+	}
+	counts := make(map[int]int)
+	sum := 0
+	for _, l := range levels {
+		if l < 1 {
+			return 0, fmt.Errorf("%w: character level %d must be at least 1", ErrInvalidEncounter, l)
+		}
+		counts[l]++
+		sum += l
+	}
+	for level, count := range counts {
+		if count*2 > len(levels) {
+			return level, nil
+		}
+	}
+	return int(math.Round(float64(sum) / float64(len(levels)))), nil
+}
+
+// effectiveSize weighs each character by what a creature of the same level
+// difference is worth, so a character one level above the party counts as
+// one and a half characters (60 XP against 40) and one level below counts as
+// three quarters. A party all at the same level weighs exactly its size.
+// The result is scaled by the XP of an on-level creature to stay integral.
+func (p Party) effectiveSize() int {
+	onLevel := creatureXpByLevelDifference[0]
+	if p.Levels == nil {
+		return p.Size * onLevel
+	}
+	total := 0
+	for _, l := range p.Levels {
+		diff := min(max(l-p.Level, MinCreatureLevelDifference), MaxCreatureLevelDifference)
+		total += creatureXpByLevelDifference[diff]
+	}
+	return total
+}
+
+// Budget returns the party's XP budget for a difficulty. For a party at one
+// level this is exactly GetXpBudget.
+func (p Party) Budget(difficulty string) (int, error) {
+	if p.Levels == nil {
+		return GetXpBudget(difficulty, p.Size)
+	}
+	base, err := GetXpBudget(difficulty, 4)
+	if err != nil {
+		return 0, err
+	}
+	adjustment, err := GetCharacterAdjustment(difficulty)
+	if err != nil {
+		return 0, err
+	}
+	onLevel := creatureXpByLevelDifference[0]
+	extra := float64(adjustment*(p.effectiveSize()-4*onLevel)) / float64(onLevel)
+	budget := base + int(math.Round(extra))
+	if budget <= 0 {
+		return 0, ErrInvalidPartySize
+	}
+	return budget, nil
+}
+
+// TierBudgets returns the party's budget for every threat tier.
+func (p Party) TierBudgets() ([]structs.TierBudget, error) {
+	var budgets []structs.TierBudget
+	for _, tier := range ThreatTiers {
+		budget, err := p.Budget(tier)
+		if err != nil {
+			return nil, err
+		}
+		adjustment, err := GetCharacterAdjustment(tier)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, structs.TierBudget{
+			Threat:              tier,
+			Budget:              budget,
+			CharacterAdjustment: adjustment,
+		})
+	}
+	return budgets, nil
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestGetMixedPartyLevel(t *testing.T) {
+	tests := []struct {
+		levels   []int
+		expected int
+	}{
+		{[]int{5, 5, 5, 5}, 5},
+		{[]int{5, 5, 5, 6}, 5},
+		{[]int{4, 5, 6, 7}, 6},
+		{[]int{3, 3, 5, 5}, 4},
+		{[]int{1, 2}, 2},
+	}
+	for _, test := range tests {
+		result, err := GetMixedPartyLevel(test.levels)
+		if err != nil {
+			t.Errorf("GetMixedPartyLevel(%v) unexpected error: %v", test.levels, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("GetMixedPartyLevel(%v) = %d; want %d", test.levels, result, test.expected)
+		}
+	}
+	if _, err := GetMixedPartyLevel(nil); err == nil {
+		t.Errorf("Expected error for an empty party")
+	}
+}
+
+func TestMixedPartyBudgetMatchesSingleLevel(t *testing.T) {
+	for _, size := range []int{1, 3, 4, 6} {
+		levels := make([]int, size)
+		for i := range levels {
+			levels[i] = 7
+		}
+		party, err := NewMixedParty(levels)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, tier := range ThreatTiers {
+			expected, _ := GetXpBudget(tier, size)
+			result, err := party.Budget(tier)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != expected {
+				t.Errorf("Budget(%s) for %d characters = %d; want %d", tier, size, result, expected)
+			}
+		}
+	}
+}
+
+func TestMixedPartyBudget(t *testing.T) {
+	// Three level 5 PCs and one level 6 PC: party level 5, the level 6 PC
+	// counts as one and a half characters.
+	party, err := NewMixedParty([]int{5, 5, 5, 6})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if party.Level != 5 {
+		t.Errorf("Expected party level 5, got %d", party.Level)
+	}
+	budget, err := party.Budget("moderate")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if budget != 90 {
+		t.Errorf("Expected moderate budget 90, got %d", budget)
+	}
+
+	// A level 3 sidekick with a level 5 party counts as half a character.
+	party, err = NewMixedParty([]int{5, 5, 5, 5, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	budget, err = party.Budget("severe")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if budget != 135 {
+		t.Errorf("Expected severe budget 135, got %d", budget)
+	}
+}
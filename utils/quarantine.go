@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// Unclassified is a pack document, or an item embedded in one, that the
+// parser could not place. Its data is left out of the bestiary, so it is kept
+// in quarantine with the raw JSON to show what a sync is losing.
+type Unclassified struct {
+	Path string
+	// Item is the name of the embedded item; it is empty when the whole
+	// document could not be classified.
+	Item   string
+	Reason string
+	Raw    string
+}
+
+// knownDocumentTypes are the Foundry document types a sync recognises. Only
+// npc and hazard documents are stored; the rest are skipped on purpose.
+var knownDocumentTypes = map[string]bool{
+	// Actors
+	"army": true, "character": true, "familiar": true, "hazard": true,
+	"loot": true, "npc": true, "party": true, "vehicle": true,
+	// Items
+	"action": true, "affliction": true, "ammo": true, "ancestry": true,
+	"armor": true, "backpack": true, "background": true, "book": true,
+	"campaignFeature": true, "class": true, "condition": true,
+	"consumable": true, "deity": true, "effect": true, "equipment": true,
+	"feat": true, "heritage": true, "kit": true, "lore": true, "melee": true,
+	"shield": true, "spell": true, "spellcastingentry": true,
+	"treasure": true, "weapon": true,
+	// Macros
+	"chat": true, "script": true,
+}
+
+// classifyDocument returns why a document cannot be classified, or an empty
+// string if it can. Journal entries and roll tables carry no type but are
+// recognised by their pages and results.
+func classifyDocument(data string) string {
+	if !gjson.Valid(data) {
+		return "invalid JSON"
+	}
+	docType := gjson.Get(data, "type")
+	switch {
+	case docType.Exists() && docType.String() == "":
+		return "document has an empty type"
+	case docType.Exists():
+		if !knownDocumentTypes[docType.String()] {
+			return fmt.Sprintf("unknown document type %q", docType.String())
+		}
+		return ""
+	case gjson.Get(data, "pages").Exists(), gjson.Get(data, "results").Exists():
+		return ""
+	case !gjson.Parse(data).IsObject():
+		// Folder listings are arrays.
+		return ""
+	}
+	return "document has no type"
+}
+
+func unclassifiedItem(item, reason string) Unclassified {
+	return Unclassified{Item: gjson.Get(item, "name").String(), Reason: reason, Raw: item}
+}
+
+// inDocument records the document path on items found inside it.
+func inDocument(path string, items []Unclassified) []Unclassified {
+	for i := range items {
+		items[i].Path = path
+	}
+	return items
+}
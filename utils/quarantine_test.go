@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/tidwall/gjson"
+)
+
+func TestClassifyDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"npc", `{"type": "npc"}`, ""},
+		{"skipped type", `{"type": "loot"}`, ""},
+		{"journal", `{"name": "Rules", "pages": []}`, ""},
+		{"roll table", `{"name": "Loot", "results": []}`, ""},
+		{"folders", `[{"name": "Folder"}]`, ""},
+		{"unknown type", `{"type": "starship"}`, `unknown document type "starship"`},
+		{"empty type", `{"type": ""}`, "document has an empty type"},
+		{"no type", `{"name": "Mystery"}`, "document has no type"},
+		{"invalid", `{"type": "npc"`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		if got := classifyDocument(tt.data); got != tt.want {
+			t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParseItemsQuarantinesUnknownItems(t *testing.T) {
+	items := gjson.Parse(`[
+		{"name": "Claw", "type": "melee", "system": {"weaponType": {"value": "melee"}}},
+		{"name": "Power Attack", "type": "feat"},
+		{"name": "Odd Action", "type": "action", "system": {"actionType": {"value": "channel"}}},
+		{"name": "Odd Casting", "type": "spellcastingentry", "system": {"prepared": {"value": "ritual"}}},
+		{"name": "Sailing Lore", "type": "lore"}
+	]`)
+	_, _, _, _, _, _, melees, _, _, unclassified, err := ParseItems(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(melees) != 1 {
+		t.Errorf("Expected 1 melee attack, got %d", len(melees))
+	}
+	want := []Unclassified{
+		{Item: "Power Attack", Reason: `unknown item type "feat"`},
+		{Item: "Odd Action", Reason: `unknown action type "channel"`},
+		{Item: "Odd Casting", Reason: `unknown spellcasting type "ritual"`},
+	}
+	if len(unclassified) != len(want) {
+		t.Fatalf("Expected %d unclassified items, got %+v", len(want), unclassified)
+	}
+	for i, item := range unclassified {
+		if item.Item != want[i].Item || item.Reason != want[i].Reason {
+			t.Errorf("Item %d: expected %s (%s), got %s (%s)", i, want[i].Item, want[i].Reason, item.Item, item.Reason)
+		}
+		if gjson.Get(item.Raw, "name").String() != item.Item {
+			t.Errorf("Item %d: raw JSON not kept, got %s", i, item.Raw)
+		}
+	}
+}
+
+func TestIngestFilesReportsUnclassifiedDocuments(t *testing.T) {
+	dir := t.TempDir()
+	docs := map[string]string{
+		"loot.json":     `{"type": "loot"}`,
+		"starship.json": `{"type": "starship"}`,
+		"broken.json":   `{"type":`,
+	}
+	var files []string
+	for _, name := range []string{"loot.json", "starship.json", "broken.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(docs[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	report := IngestFiles(context.Background(), config.Config{}, files, time.Now(), IngestOptions{ParseWorkers: 2})
+	if report.Skipped != 3 || report.Failed != 0 {
+		t.Fatalf("Expected every document to be skipped, got %+v", report)
+	}
+	if len(report.Unclassified) != 2 {
+		t.Fatalf("Expected 2 unclassified documents, got %+v", report.Unclassified)
+	}
+	if report.Unclassified[0].Path != files[1] || report.Unclassified[1].Path != files[2] {
+		t.Errorf("Expected unclassified documents in file order, got %+v", report.Unclassified)
+	}
+	if report.Unclassified[1].Raw != docs["broken.json"] {
+		t.Errorf("Expected raw document to be kept, got '%s'", report.Unclassified[1].Raw)
+	}
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+)
+
+// LatestRelease as SYNC_RELEASE follows the newest release that is neither a
+// draft nor a prerelease.
+const LatestRelease = "latest"
+
+var (
+	ErrNotGitHubRepo   = errors.New("REPO_URL is not a GitHub API tarball URL, so it has no releases")
+	ErrReleaseNotFound = errors.New("release not found")
+)
+
+// UpstreamRelease is a published release of the upstream repository.
+type UpstreamRelease struct {
+	Tag         string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	TarballURL  string    `json:"tarball_url"`
+}
+
+// githubRepo is a repository on the GitHub REST API.
+type githubRepo struct {
+	api   string
+	owner string
+	name  string
+}
+
+func (r githubRepo) url(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/%s", r.api, r.owner, r.name, path)
+}
+
+// upstreamRepo returns the repository repoURL downloads from. Only
+// api.github.com tarball URLs name one.
+func upstreamRepo(repoURL string) (githubRepo, bool) {
+	m := githubTarballURL.FindStringSubmatch(repoURL)
+	if m == nil {
+		return githubRepo{}, false
+	}
+	return githubRepo{api: "https://api.github.com", owner: m[1], name: m[2]}, true
+}
+
+// ListUpstreamReleases returns the releases of the repository REPO_URL
+// downloads from, newest first. Drafts are left out.
+func ListUpstreamReleases(ctx context.Context, cfg config.Config) ([]UpstreamRelease, error) {
+	repo, ok := upstreamRepo(cfg.REPO_URL)
+	if !ok {
+		return nil, ErrNotGitHubRepo
+	}
+	return fetchReleases(ctx, http.DefaultClient, repo, cfg.GH_TOKEN)
+}
+
+// resolveRelease points cfg at the tarball of the release SYNC_RELEASE
+// selects and returns its tag. cfg is returned unchanged when SYNC_RELEASE is
+// unset.
+func resolveRelease(ctx context.Context, cfg config.Config) (config.Config, string, error) {
+	if cfg.SYNC_RELEASE == "" {
+		return cfg, "", nil
+	}
+	repo, ok := upstreamRepo(cfg.REPO_URL)
+	if !ok {
+		return cfg, "", ErrNotGitHubRepo
+	}
+	release, err := fetchRelease(ctx, http.DefaultClient, repo, cfg.GH_TOKEN, cfg.SYNC_RELEASE)
+	if err != nil {
+		return cfg, "", err
+	}
+	logger.Log.Info("Syncing upstream release", "selected", cfg.SYNC_RELEASE, "tag", release.Tag)
+	cfg.REPO_URL = release.TarballURL
+	return cfg, release.Tag, nil
+}
+
+func fetchReleases(ctx context.Context, client *http.Client, repo githubRepo, token string) ([]UpstreamRelease, error) {
+	var releases []UpstreamRelease
+	if err := getGitHubJSON(ctx, client, repo.url("releases?per_page=100"), token, &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases %w", err)
+	}
+	published := releases[:0]
+	for _, release := range releases {
+		if !release.Draft {
+			published = append(published, release)
+		}
+	}
+	return published, nil
+}
+
+// fetchRelease looks up the release tagged tag, or the latest stable release
+// for LatestRelease.
+func fetchRelease(ctx context.Context, client *http.Client, repo githubRepo, token string, tag string) (UpstreamRelease, error) {
+	path := "releases/tags/" + url.PathEscape(tag)
+	if tag == LatestRelease {
+		path = "releases/latest"
+	}
+	var release UpstreamRelease
+	err := getGitHubJSON(ctx, client, repo.url(path), token, &release)
+	var statusErr *DownloadStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return release, fmt.Errorf("%w: %s", ErrReleaseNotFound, tag)
+	}
+	if err != nil {
+		return release, fmt.Errorf("failed to look up release %s %w", tag, err)
+	}
+	if release.TarballURL == "" {
+		return release, fmt.Errorf("release %s has no tarball", release.Tag)
+	}
+	return release, nil
+}
+
+// getGitHubJSON decodes the JSON document at endpoint into v. Any status but 200
+// is returned as a *DownloadStatusError.
+func getGitHubJSON(ctx context.Context, client *http.Client, endpoint string, token string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "MyGoClient/1.0")
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &DownloadStatusError{URL: endpoint, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s %w", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// releasesStandIn serves the GitHub releases API for foundryvtt/pf2e.
+func releasesStandIn(t *testing.T) githubRepo {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/foundryvtt/pf2e/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"tag_name": "7.2.0-beta", "prerelease": true, "tarball_url": "https://api.github.com/repos/foundryvtt/pf2e/tarball/7.2.0-beta"},
+			{"tag_name": "7.1.1", "name": "Errata", "draft": true},
+			{"tag_name": "7.1.0", "name": "Player Core 2", "published_at": "2025-05-01T12:00:00Z", "tarball_url": "https://api.github.com/repos/foundryvtt/pf2e/tarball/7.1.0"}
+		]`))
+	})
+	mux.HandleFunc("GET /repos/foundryvtt/pf2e/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "7.1.0", "tarball_url": "https://api.github.com/repos/foundryvtt/pf2e/tarball/7.1.0"}`))
+	})
+	mux.HandleFunc("GET /repos/foundryvtt/pf2e/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("tag") != "6.12.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "6.12.0", "tarball_url": "https://api.github.com/repos/foundryvtt/pf2e/tarball/6.12.0"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return githubRepo{api: server.URL, owner: "foundryvtt", name: "pf2e"}
+}
+
+func TestUpstreamRepo(t *testing.T) {
+	repo, ok := upstreamRepo("https://api.github.com/repos/foundryvtt/pf2e/tarball/master")
+	if !ok || repo.url("releases") != "https://api.github.com/repos/foundryvtt/pf2e/releases" {
+		t.Errorf("Unexpected repo %+v", repo)
+	}
+	if _, ok := upstreamRepo("https://example.com/pf2e.tar.gz"); ok {
+		t.Error("Expected a URL outside the GitHub API to have no repo")
+	}
+}
+
+func TestFetchReleases(t *testing.T) {
+	repo := releasesStandIn(t)
+	releases, err := fetchReleases(context.Background(), http.DefaultClient, repo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 || releases[0].Tag != "7.2.0-beta" || !releases[0].Prerelease || releases[1].Tag != "7.1.0" {
+		t.Fatalf("Expected the published releases, got %+v", releases)
+	}
+	if releases[1].PublishedAt.IsZero() {
+		t.Error("Expected the publish date to be decoded")
+	}
+}
+
+func TestFetchRelease(t *testing.T) {
+	repo := releasesStandIn(t)
+	tests := []struct {
+		selected string
+		want     string
+	}{
+		{LatestRelease, "7.1.0"},
+		{"6.12.0", "6.12.0"},
+	}
+	for _, tt := range tests {
+		release, err := fetchRelease(context.Background(), http.DefaultClient, repo, "", tt.selected)
+		if err != nil || release.Tag != tt.want || release.TarballURL != "https://api.github.com/repos/foundryvtt/pf2e/tarball/"+tt.want {
+			t.Errorf("%s: expected release %s, got %+v (%v)", tt.selected, tt.want, release, err)
+		}
+	}
+	if _, err := fetchRelease(context.Background(), http.DefaultClient, repo, "", "1.0.0"); !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("Expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestSyncSourceDataVersion(t *testing.T) {
+	if got := (SyncSource{Version: "abc123", Release: "7.1.0"}).DataVersion(); got != "7.1.0" {
+		t.Errorf("Expected the release tag, got %s", got)
+	}
+	if got := (SyncSource{Version: "abc123"}).DataVersion(); got != "abc123" {
+		t.Errorf("Expected the commit, got %s", got)
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/robfig/cron/v3"
+)
+
+// ErrSyncInProgress is returned when a sync on this or another instance
+// sharing the database already holds the sync lock.
+var ErrSyncInProgress = errors.New("a sync is already running")
+
+// syncLockKey identifies the Postgres advisory lock held for the length of a
+// sync.
+const syncLockKey int64 = 0x62657374696172
+
+// syncLock is a session advisory lock. It is held on a connection taken out
+// of the pool so the session outlives the queries the sync itself runs.
+type syncLock struct {
+	conn *pgxpool.Conn
+}
+
+func acquireSyncLock(ctx context.Context, pool *pgxpool.Pool) (*syncLock, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire a connection for the sync lock %w", err)
+	}
+	var locked bool
+	err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", syncLockKey).Scan(&locked)
+	if err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to take the sync lock %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return nil, ErrSyncInProgress
+	}
+	return &syncLock{conn: conn}, nil
+}
+
+func (l *syncLock) release() {
+	ctx := context.Background()
+	if _, err := l.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", syncLockKey); err != nil {
+		// Ending the session frees the lock as well.
+		logger.Log.Error("Failed to release the sync lock, closing its connection", "err", err)
+		l.conn.Conn().Close(ctx)
+	}
+	l.conn.Release()
+}
+
+// SyncScheduler runs syncs on the configured cron schedule and on demand.
+type SyncScheduler struct {
+	cfg     config.Config
+	sources *SourceRegistry
+	cron    *cron.Cron
+	ctx     context.Context
+	cancel  context.CancelFunc
+	runs    sync.WaitGroup
+}
+
+// NewSyncScheduler validates the schedule, timezone and data sources in cfg.
+// Nothing runs until Start is called.
+func NewSyncScheduler(cfg config.Config) (*SyncScheduler, error) {
+	loc, err := time.LoadLocation(cfg.SYNC_TIMEZONE)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync timezone %q: %w", cfg.SYNC_TIMEZONE, err)
+	}
+	sources, err := SourcesFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &SyncScheduler{cfg: cfg, sources: sources, cron: cron.New(cron.WithLocation(loc))}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if _, err := s.cron.AddFunc(cfg.SYNC_SCHEDULE, s.scheduled); err != nil {
+		return nil, fmt.Errorf("invalid sync schedule %q: %w", cfg.SYNC_SCHEDULE, err)
+	}
+	return s, nil
+}
+
+// Start begins running the schedule in the background.
+func (s *SyncScheduler) Start() {
+	s.cron.Start()
+	logger.Log.Info("Scheduled sync", "schedule", s.cfg.SYNC_SCHEDULE, "timezone", s.cfg.SYNC_TIMEZONE)
+}
+
+func (s *SyncScheduler) scheduled() {
+	_, done, err := s.start()
+	if errors.Is(err, ErrSyncInProgress) {
+		logger.Log.Warn("Skipping scheduled sync, another sync is running")
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Scheduled sync failed to start", "err", err)
+		return
+	}
+	<-done
+}
+
+// Trigger starts a sync in the background and returns the ids of its runs,
+// one per data source in sync order.
+func (s *SyncScheduler) Trigger() ([]int32, error) {
+	runIDs, _, err := s.start()
+	return runIDs, err
+}
+
+// start takes the sync lock and syncs every source in the background. The
+// channel is closed once every run has been recorded.
+func (s *SyncScheduler) start() ([]int32, <-chan struct{}, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	lock, runs, err := beginSync(s.ctx, s.cfg, s.sources.Sources())
+	if err != nil {
+		return nil, nil, err
+	}
+	runIDs := make([]int32, len(runs))
+	for i, run := range runs {
+		runIDs[i] = run.id
+	}
+	done := make(chan struct{})
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		defer close(done)
+		defer lock.release()
+		logger.Log.Info("Sync started", "runs", runIDs)
+		if err := finishSyncs(s.ctx, s.cfg, runs, nil); err != nil {
+			logger.Log.Error("Sync failed", "runs", runIDs, "err", err)
+			return
+		}
+		logger.Log.Info("Sync finished", "runs", runIDs)
+	}()
+	return runIDs, done, nil
+}
+
+// Stop stops the schedule, cancels any running sync and waits until it has
+// recorded its outcome or ctx ends.
+func (s *SyncScheduler) Stop(ctx context.Context) error {
+	s.cancel()
+	stopped := s.cron.Stop()
+	finished := make(chan struct{})
+	go func() {
+		<-stopped.Done()
+		s.runs.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+func TestNewSyncSchedulerValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		timezone string
+		wantErr  bool
+	}{
+		{"default", "0 3 * * 2", "America/Los_Angeles", false},
+		{"utc", "*/15 * * * *", "UTC", false},
+		{"bad timezone", "0 3 * * 2", "Mars/Olympus_Mons", true},
+		{"bad schedule", "every tuesday", "UTC", true},
+		{"seconds field", "0 0 3 * * 2", "UTC", true},
+	}
+	for _, tt := range tests {
+		_, err := NewSyncScheduler(config.Config{SYNC_SCHEDULE: tt.schedule, SYNC_TIMEZONE: tt.timezone})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSyncSchedulerStopsCleanly(t *testing.T) {
+	scheduler, err := NewSyncScheduler(config.Config{SYNC_SCHEDULE: "0 3 * * 2", SYNC_TIMEZONE: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduler.Start()
+	if err := scheduler.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected a clean stop, got %v", err)
+	}
+	if _, err := scheduler.Trigger(); err == nil {
+		t.Error("Expected a stopped scheduler to refuse new syncs")
+	}
+}
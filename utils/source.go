@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+// DefaultSource is the name of the Pathfinder 2e system REPO_URL points at.
+// Records stored before sources were namespaced belong to it.
+const DefaultSource = "pf2e"
+
+// ErrUnknownSource is returned for a source name that is not registered.
+var ErrUnknownSource = errors.New("unknown data source")
+
+// Source is a Foundry repository syncs load: the pf2e system, or another
+// system or module that shares its document format, such as Starfinder 2e or
+// a homebrew bestiary. Every record is stored under the Name of the source it
+// was loaded from, so sources never update or retire each other's records.
+type Source struct {
+	// Name namespaces the records of the source and is what the search
+	// endpoints filter on.
+	Name string
+	// Packs restricts the source to packs matching these patterns, see
+	// ImportOptions.Packs. Unlike an import filter it applies to every sync
+	// of the source, so records missing from those packs are still retired.
+	// An empty list includes every pack.
+	Packs []string
+	fetch fetcher
+	parse documentParser
+}
+
+// fetcher gets what a sync of a source loads. It returns ErrUpstreamUnchanged
+// as soon as it can tell nothing changed since last.
+type fetcher func(ctx context.Context, cfg config.Config, last SyncSource, hasLast bool) (syncInput, error)
+
+// documentParser parses one document of a source. It returns nil for
+// documents that are unchanged or that the bestiary does not store.
+type documentParser func(ctx context.Context, cfg config.Config, doc SyncDocument, data []byte) (*parsedDocument, error)
+
+// documentParsers are the document formats a source can be read as, by the
+// name SYNC_SOURCES gives them.
+var documentParsers = map[string]documentParser{
+	// npc and hazard actors as the pf2e system stores them. Starfinder 2e
+	// and bestiary modules built for pf2e use the same shape.
+	"pf2e": decodeDocument,
+}
+
+// githubFetcher downloads repoURL, or the release of it that release selects,
+// the way fetchUpstream downloads REPO_URL. checksum is what the archive must
+// match, as SYNC_ARCHIVE_SHA256 is for REPO_URL.
+func githubFetcher(repoURL string, release string, checksum string) fetcher {
+	return func(ctx context.Context, cfg config.Config, last SyncSource, hasLast bool) (syncInput, error) {
+		cfg.REPO_URL = repoURL
+		cfg.SYNC_RELEASE = release
+		cfg.SYNC_ARCHIVE_SHA256 = checksum
+		return fetchUpstream(ctx, cfg, last, hasLast)
+	}
+}
+
+// defaultSource is the pf2e system, downloaded from REPO_URL.
+func defaultSource() Source {
+	return Source{Name: DefaultSource, fetch: fetchUpstream, parse: decodeDocument}
+}
+
+var sourceName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// SourceRegistry holds the data sources syncs load, in the order they sync.
+type SourceRegistry struct {
+	sources []Source
+}
+
+func (r *SourceRegistry) register(source Source) error {
+	if !sourceName.MatchString(source.Name) {
+		return fmt.Errorf("invalid source name %q: use up to 50 lower case letters, digits and dashes", source.Name)
+	}
+	if _, ok := r.Lookup(source.Name); ok {
+		return fmt.Errorf("source %s is registered twice", source.Name)
+	}
+	for _, pattern := range source.Packs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pack pattern %q for source %s: %w", pattern, source.Name, err)
+		}
+	}
+	r.sources = append(r.sources, source)
+	return nil
+}
+
+// Lookup returns the source registered under name.
+func (r *SourceRegistry) Lookup(name string) (Source, bool) {
+	for _, source := range r.sources {
+		if source.Name == name {
+			return source, true
+		}
+	}
+	return Source{}, false
+}
+
+// Sources returns every registered source in sync order.
+func (r *SourceRegistry) Sources() []Source {
+	return r.sources
+}
+
+// sourceSettings is one entry of SYNC_SOURCES.
+type sourceSettings struct {
+	Name    string   `json:"name"`
+	RepoURL string   `json:"repo_url"`
+	Release string   `json:"release"`
+	SHA256  string   `json:"sha256"`
+	Packs   []string `json:"packs"`
+	// Parser names an entry of documentParsers; pf2e when empty.
+	Parser string `json:"parser"`
+}
+
+// SourcesFromConfig registers the pf2e system REPO_URL points at, followed by
+// every source SYNC_SOURCES lists.
+func SourcesFromConfig(cfg config.Config) (*SourceRegistry, error) {
+	registry := &SourceRegistry{}
+	if err := registry.register(defaultSource()); err != nil {
+		return nil, err
+	}
+	if cfg.SYNC_SOURCES == "" {
+		return registry, nil
+	}
+	var settings []sourceSettings
+	if err := json.Unmarshal([]byte(cfg.SYNC_SOURCES), &settings); err != nil {
+		return nil, fmt.Errorf("failed to read SYNC_SOURCES %w", err)
+	}
+	for _, s := range settings {
+		if s.RepoURL == "" {
+			return nil, fmt.Errorf("source %s has no repo_url", s.Name)
+		}
+		parserName := s.Parser
+		if parserName == "" {
+			parserName = "pf2e"
+		}
+		parse, ok := documentParsers[parserName]
+		if !ok {
+			return nil, fmt.Errorf("source %s has an unknown parser %q", s.Name, s.Parser)
+		}
+		source := Source{
+			Name:  s.Name,
+			Packs: s.Packs,
+			fetch: githubFetcher(s.RepoURL, s.Release, s.SHA256),
+			parse: parse,
+		}
+		if err := registry.register(source); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
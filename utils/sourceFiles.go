@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+// sourceFile is a file a sync read without error, keyed by its path inside
+// the archive of its data source so the key survives the archive directory
+// changing name with every commit.
+type sourceFile struct {
+	key string
+	doc SyncDocument
+}
+
+// sourceKey returns path relative to root with forward slashes, or path
+// itself if it is not under root.
+func sourceKey(root string, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// loadSourceHashes returns the content hash of every file the last syncs of
+// the data source named source read, by key.
+func loadSourceHashes(ctx context.Context, cfg config.Config, source string) (map[string]string, error) {
+	rows, err := writeMonsters.New(cfg.DBPool).ListSourceFiles(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source file hashes %w", err)
+	}
+	hashes := make(map[string]string, len(rows))
+	for _, row := range rows {
+		hashes[row.Path] = row.ContentHash
+	}
+	return hashes, nil
+}
+
+// recordSourceFiles stores the hash of every file a sync of the data source
+// named source read and marks the records loaded from them as seen, which
+// covers the unchanged files that were not parsed.
+func recordSourceFiles(ctx context.Context, cfg config.Config, source string, files []sourceFile, seenAt time.Time) error {
+	params := writeMonsters.UpsertSourceFilesParams{
+		Source:        source,
+		Paths:         make([]string, len(files)),
+		FoundryIds:    make([]string, len(files)),
+		PackPaths:     make([]string, len(files)),
+		ContentHashes: make([]string, len(files)),
+		SeenAt:        NewTimestamptz(seenAt),
+	}
+	for i, file := range files {
+		params.Paths[i] = file.key
+		params.FoundryIds[i] = file.doc.FoundryID
+		params.PackPaths[i] = file.doc.PackPath
+		params.ContentHashes[i] = file.doc.ContentHash
+	}
+
+	tx, err := cfg.DBPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := writeMonsters.New(tx)
+	if err := queries.UpsertSourceFiles(ctx, params); err != nil {
+		return fmt.Errorf("failed to record source files %w", err)
+	}
+	if _, err := queries.MarkMonstersSeenFromSourceFiles(ctx, params.SeenAt); err != nil {
+		return fmt.Errorf("failed to mark monsters seen %w", err)
+	}
+	if _, err := queries.MarkHazardsSeenFromSourceFiles(ctx, params.SeenAt); err != nil {
+		return fmt.Errorf("failed to mark hazards seen %w", err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+func TestSourcesFromConfig(t *testing.T) {
+	registry, err := SourcesFromConfig(config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources := registry.Sources(); len(sources) != 1 || sources[0].Name != DefaultSource {
+		t.Errorf("Expected only the pf2e system, got %+v", sources)
+	}
+
+	registry, err = SourcesFromConfig(config.Config{SYNC_SOURCES: `[
+		{"name": "sf2e", "repo_url": "https://api.github.com/repos/foundryvtt/sf2e/tarball", "release": "latest", "packs": ["*-bestiary"]}
+	]`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, source := range registry.Sources() {
+		names = append(names, source.Name)
+	}
+	if !slices.Equal(names, []string{DefaultSource, "sf2e"}) {
+		t.Errorf("Expected pf2e then sf2e, got %v", names)
+	}
+	source, ok := registry.Lookup("sf2e")
+	if !ok || !slices.Equal(source.Packs, []string{"*-bestiary"}) || source.fetch == nil || source.parse == nil {
+		t.Errorf("Unexpected sf2e source %+v", source)
+	}
+	if _, ok := registry.Lookup("homebrew"); ok {
+		t.Error("Expected an unregistered source to be missing")
+	}
+}
+
+func TestSourcesFromConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources string
+	}{
+		{"malformed JSON", `{"name": "sf2e"}`},
+		{"missing repo_url", `[{"name": "sf2e"}]`},
+		{"unknown parser", `[{"name": "sf2e", "repo_url": "https://example.com/sf2e.tar.gz", "parser": "dnd5e"}]`},
+		{"invalid name", `[{"name": "Starfinder 2e", "repo_url": "https://example.com/sf2e.tar.gz"}]`},
+		{"duplicate name", `[{"name": "pf2e", "repo_url": "https://example.com/pf2e.tar.gz"}]`},
+		{"malformed pack pattern", `[{"name": "sf2e", "repo_url": "https://example.com/sf2e.tar.gz", "packs": ["[bestiary"]}]`},
+	}
+	for _, tt := range tests {
+		if _, err := SourcesFromConfig(config.Config{SYNC_SOURCES: tt.sources}); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestPackArchiveIncludesSourcePacks(t *testing.T) {
+	path := writeArchive(t, writeArchiveTree(t))
+	archive := newPackArchive(path, []string{"*-bestiary"})
+	archive.include = []string{"pathfinder-bestiary"}
+	docs := readArchive(t, context.Background(), archive, path)
+	if len(docs) != 1 || docs[0].key != "packs/pathfinder-bestiary/goblin.json" {
+		t.Errorf("Expected only the packs both filters pass, got %v", docs)
+	}
+}
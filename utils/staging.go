@@ -0,0 +1,420 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// A sync loads into StagingSchema, checks it, and then swaps it in as
+// config.LiveSchema. The generation it replaced is kept as PreviousSchema
+// until the next successful sync so it can be restored with RollbackSync.
+const (
+	StagingSchema  = "bestiary_staging"
+	PreviousSchema = "bestiary_previous"
+	swapSchema     = "bestiary_swap"
+)
+
+// Integrity thresholds for a staged load.
+const (
+	// maxParseFailureRatio is the share of files that may fail to load.
+	maxParseFailureRatio = 0.05
+	// minRetainedRatio is the share of the live monsters and hazards the
+	// staged generation must still have as active records.
+	minRetainedRatio = 0.9
+)
+
+var (
+	ErrIntegrityCheck       = errors.New("staged data failed integrity checks")
+	ErrNoPreviousGeneration = errors.New("no previous generation to roll back to")
+)
+
+// LoadReport counts the files a sync tried to load into staging and what
+// became of them. Skipped files were unchanged since the last sync or are
+// not a type the bestiary stores.
+type LoadReport struct {
+	Files    int
+	Parsed   int
+	Inserted int
+	Updated  int
+	Skipped  int
+	Failed   int
+	// Failures lists the files that failed, in the order they were given.
+	Failures []FileFailure
+	// Unclassified lists the documents and embedded items the parser could
+	// not place, in the same order.
+	Unclassified []Unclassified
+	// sources are the files read without error, unchanged ones included.
+	sources []sourceFile
+}
+
+// FileFailure is a file that could not be loaded and why.
+type FileFailure struct {
+	Path string
+	Err  error
+}
+
+// StagingStats are the row counts the integrity checks compare.
+type StagingStats struct {
+	LiveMonsters   int64
+	StagedMonsters int64
+	LiveHazards    int64
+	StagedHazards  int64
+	// Orphans counts child rows whose parent key is NULL, keyed by
+	// "table.column".
+	Orphans map[string]int64
+}
+
+// CheckIntegrity returns a description of every check the staged load fails.
+// An empty result means the staging schema is safe to swap in.
+func CheckIntegrity(stats StagingStats, report LoadReport) []string {
+	var problems []string
+	if report.Files == 0 {
+		problems = append(problems, "no files were loaded")
+	} else if ratio := float64(report.Failed) / float64(report.Files); ratio > maxParseFailureRatio {
+		problems = append(problems, fmt.Sprintf("%d of %d files failed to load (%.1f%%, limit %.1f%%)",
+			report.Failed, report.Files, ratio*100, maxParseFailureRatio*100))
+	}
+	if stats.StagedMonsters == 0 {
+		problems = append(problems, "staging has no active monsters")
+	} else if float64(stats.StagedMonsters) < float64(stats.LiveMonsters)*minRetainedRatio {
+		problems = append(problems, fmt.Sprintf("staging has %d active monsters, live has %d",
+			stats.StagedMonsters, stats.LiveMonsters))
+	}
+	if float64(stats.StagedHazards) < float64(stats.LiveHazards)*minRetainedRatio {
+		problems = append(problems, fmt.Sprintf("staging has %d active hazards, live has %d",
+			stats.StagedHazards, stats.LiveHazards))
+	}
+	var orphaned []string
+	for column, count := range stats.Orphans {
+		if count > 0 {
+			orphaned = append(orphaned, fmt.Sprintf("%s (%d)", column, count))
+		}
+	}
+	if len(orphaned) > 0 {
+		slices.Sort(orphaned)
+		problems = append(problems, "orphaned rows in "+strings.Join(orphaned, ", "))
+	}
+	return problems
+}
+
+// rowQuerier is satisfied by both a pool and a transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func schemaExists(ctx context.Context, q rowQuerier, name string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)", name).Scan(&exists)
+	return exists, err
+}
+
+func tableExists(ctx context.Context, q rowQuerier, schemaName string, table string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_tables WHERE schemaname = $1 AND tablename = $2)", schemaName, table).Scan(&exists)
+	return exists, err
+}
+
+// EnsureLiveSchema creates the live schema from the embedded DDL if it does
+// not exist yet, so a fresh database can serve requests before its first
+// sync. An existing live schema is migrated to the current DDL instead, as
+// the API reads it until a sync swaps in a new one.
+func EnsureLiveSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	exists, err := schemaExists(ctx, pool, config.LiveSchema)
+	if err != nil {
+		return fmt.Errorf("failed to look up schema %s %w", config.LiveSchema, err)
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if !exists {
+		if err := createSchema(ctx, tx, config.LiveSchema); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL search_path TO "+pgx.Identifier{config.LiveSchema}.Sanitize()); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, schema.MigrateDDL); err != nil {
+		return fmt.Errorf("failed to migrate schema %s %w", config.LiveSchema, err)
+	}
+	return tx.Commit(ctx)
+}
+
+func createSchema(ctx context.Context, tx pgx.Tx, name string) error {
+	ident := pgx.Identifier{name}.Sanitize()
+	if _, err := tx.Exec(ctx, "CREATE SCHEMA "+ident); err != nil {
+		return fmt.Errorf("failed to create schema %s %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, "SET LOCAL search_path TO "+ident); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, schema.DDL); err != nil {
+		return fmt.Errorf("failed to create tables in %s %w", name, err)
+	}
+	return nil
+}
+
+// PrepareStaging rebuilds the staging schema as a copy of the live data. The
+// sync then upserts into the copy, so unchanged files can still be skipped by
+// content hash.
+func PrepareStaging(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	staging := pgx.Identifier{StagingSchema}.Sanitize()
+	if _, err := tx.Exec(ctx, "DROP SCHEMA IF EXISTS "+staging+" CASCADE"); err != nil {
+		return fmt.Errorf("failed to drop old staging schema %w", err)
+	}
+	if err := createSchema(ctx, tx, StagingSchema); err != nil {
+		return err
+	}
+	live, err := schemaExists(ctx, tx, config.LiveSchema)
+	if err != nil {
+		return err
+	}
+	if live {
+		for _, table := range schema.Tables() {
+			// Tables added to the schema since the live generation was
+			// created start out empty.
+			exists, err := tableExists(ctx, tx, config.LiveSchema, table)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+			// Likewise columns added since then start out NULL.
+			columns, err := sharedColumns(ctx, tx, table)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+				pgx.Identifier{StagingSchema, table}.Sanitize(), columns, columns,
+				pgx.Identifier{config.LiveSchema, table}.Sanitize()))
+			if err != nil {
+				return fmt.Errorf("failed to copy %s into staging %w", table, err)
+			}
+		}
+		if err := resetSequences(ctx, tx, StagingSchema); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// sharedColumns returns the columns table has in both the live and staging
+// schemas, quoted and comma separated.
+func sharedColumns(ctx context.Context, tx pgx.Tx, table string) (string, error) {
+	rows, err := tx.Query(ctx, `SELECT s.column_name FROM information_schema.columns s
+		JOIN information_schema.columns l
+		  ON l.table_schema = $1 AND l.table_name = s.table_name AND l.column_name = s.column_name
+		WHERE s.table_schema = $2 AND s.table_name = $3
+		ORDER BY s.ordinal_position`, config.LiveSchema, StagingSchema, table)
+	if err != nil {
+		return "", err
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("failed to list the columns of %s %w", table, err)
+	}
+	columns := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// resetSequences moves every serial column in schemaName past the rows that
+// were copied in.
+func resetSequences(ctx context.Context, tx pgx.Tx, schemaName string) error {
+	rows, err := tx.Query(ctx, `SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = $1 AND column_default LIKE 'nextval(%'`, schemaName)
+	if err != nil {
+		return err
+	}
+	type serial struct{ table, column string }
+	serials, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (serial, error) {
+		var s serial
+		err := row.Scan(&s.table, &s.column)
+		return s, err
+	})
+	if err != nil {
+		return err
+	}
+	for _, s := range serials {
+		table := pgx.Identifier{schemaName, s.table}.Sanitize()
+		column := pgx.Identifier{s.column}.Sanitize()
+		_, err := tx.Exec(ctx, fmt.Sprintf("SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX(%s), 0) + 1, false) FROM %s", column, table),
+			table, s.column)
+		if err != nil {
+			return fmt.Errorf("failed to reset sequence for %s.%s %w", s.table, s.column, err)
+		}
+	}
+	return nil
+}
+
+// OpenStaging prepares the staging schema and returns a copy of cfg whose pool
+// writes to it. The caller closes the returned pool when the load is done.
+func OpenStaging(ctx context.Context, cfg config.Config) (config.Config, error) {
+	if err := PrepareStaging(ctx, cfg.DBPool); err != nil {
+		return cfg, err
+	}
+	pool, err := config.NewPool(ctx, cfg.DB_CONNECTION, StagingSchema)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to open staging pool %w", err)
+	}
+	staged := cfg
+	staged.DBPool = pool
+	return staged, nil
+}
+
+func countActive(ctx context.Context, pool *pgxpool.Pool, schemaName, table string) (int64, error) {
+	var count int64
+	err := pool.QueryRow(ctx, fmt.Sprintf("SELECT count(*) FROM %s WHERE retired_at IS NULL",
+		pgx.Identifier{schemaName, table}.Sanitize())).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count %s.%s %w", schemaName, table, err)
+	}
+	return count, nil
+}
+
+// GatherStagingStats counts active records in the live and staging schemas
+// and looks for child rows that lost their parent.
+func GatherStagingStats(ctx context.Context, pool *pgxpool.Pool) (StagingStats, error) {
+	stats := StagingStats{Orphans: make(map[string]int64)}
+	live, err := schemaExists(ctx, pool, config.LiveSchema)
+	if err != nil {
+		return stats, err
+	}
+	if live {
+		if stats.LiveMonsters, err = countActive(ctx, pool, config.LiveSchema, "monsters"); err != nil {
+			return stats, err
+		}
+		if stats.LiveHazards, err = countActive(ctx, pool, config.LiveSchema, "hazards"); err != nil {
+			return stats, err
+		}
+	}
+	if stats.StagedMonsters, err = countActive(ctx, pool, StagingSchema, "monsters"); err != nil {
+		return stats, err
+	}
+	if stats.StagedHazards, err = countActive(ctx, pool, StagingSchema, "hazards"); err != nil {
+		return stats, err
+	}
+
+	// Every ON DELETE CASCADE key points a child row at its owner; a NULL
+	// there means the row was written without one.
+	rows, err := pool.Query(ctx, `SELECT cl.relname, a.attname
+		FROM pg_constraint c
+		JOIN pg_class cl ON cl.oid = c.conrelid
+		JOIN pg_namespace n ON n.oid = cl.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[1]
+		WHERE c.contype = 'f' AND c.confdeltype = 'c' AND n.nspname = $1`, StagingSchema)
+	if err != nil {
+		return stats, err
+	}
+	parentKeys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([2]string, error) {
+		var key [2]string
+		err := row.Scan(&key[0], &key[1])
+		return key, err
+	})
+	if err != nil {
+		return stats, err
+	}
+	for _, key := range parentKeys {
+		var count int64
+		err := pool.QueryRow(ctx, fmt.Sprintf("SELECT count(*) FROM %s WHERE %s IS NULL",
+			pgx.Identifier{StagingSchema, key[0]}.Sanitize(), pgx.Identifier{key[1]}.Sanitize())).Scan(&count)
+		if err != nil {
+			return stats, fmt.Errorf("failed to check %s.%s for orphans %w", key[0], key[1], err)
+		}
+		stats.Orphans[key[0]+"."+key[1]] = count
+	}
+	return stats, nil
+}
+
+func renameSchema(ctx context.Context, tx pgx.Tx, from, to string) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s",
+		pgx.Identifier{from}.Sanitize(), pgx.Identifier{to}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("failed to rename schema %s to %s %w", from, to, err)
+	}
+	return nil
+}
+
+// SwapStaging makes the staging schema live in a single transaction. The old
+// live schema becomes the previous generation, replacing the one before it.
+func SwapStaging(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DROP SCHEMA IF EXISTS "+pgx.Identifier{PreviousSchema}.Sanitize()+" CASCADE"); err != nil {
+		return fmt.Errorf("failed to drop previous generation %w", err)
+	}
+	live, err := schemaExists(ctx, tx, config.LiveSchema)
+	if err != nil {
+		return err
+	}
+	if live {
+		if err := renameSchema(ctx, tx, config.LiveSchema, PreviousSchema); err != nil {
+			return err
+		}
+	}
+	if err := renameSchema(ctx, tx, StagingSchema, config.LiveSchema); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	// Pooled connections cache statements planned against the old tables.
+	pool.Reset()
+	return nil
+}
+
+// RollbackSync swaps the live schema with the previous generation. Running it
+// again undoes the rollback.
+func RollbackSync(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	previous, err := schemaExists(ctx, tx, PreviousSchema)
+	if err != nil {
+		return err
+	}
+	if !previous {
+		return ErrNoPreviousGeneration
+	}
+	if err := renameSchema(ctx, tx, config.LiveSchema, swapSchema); err != nil {
+		return err
+	}
+	if err := renameSchema(ctx, tx, PreviousSchema, config.LiveSchema); err != nil {
+		return err
+	}
+	if err := renameSchema(ctx, tx, swapSchema, PreviousSchema); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	pool.Reset()
+	return nil
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	healthy := StagingStats{
+		LiveMonsters:   1000,
+		StagedMonsters: 1010,
+		LiveHazards:    100,
+		StagedHazards:  100,
+		Orphans:        map[string]int64{"monster_traits.monster_id": 0},
+	}
+	tests := []struct {
+		name   string
+		stats  StagingStats
+		report LoadReport
+		want   []string
+	}{
+		{"healthy", healthy, LoadReport{Files: 1100, Failed: 3}, nil},
+		{"first load", StagingStats{StagedMonsters: 50}, LoadReport{Files: 50}, nil},
+		{"no files", healthy, LoadReport{}, []string{"no files were loaded"}},
+		{"too many failures", healthy, LoadReport{Files: 100, Failed: 6}, []string{"6 of 100 files failed"}},
+		{"empty staging", StagingStats{LiveMonsters: 10}, LoadReport{Files: 10}, []string{"no active monsters"}},
+		{"lost monsters", StagingStats{LiveMonsters: 1000, StagedMonsters: 800}, LoadReport{Files: 800}, []string{"800 active monsters"}},
+		{"lost hazards", StagingStats{StagedMonsters: 10, LiveHazards: 100, StagedHazards: 50}, LoadReport{Files: 60}, []string{"50 active hazards"}},
+		{
+			"orphans",
+			StagingStats{StagedMonsters: 10, Orphans: map[string]int64{"monster_traits.monster_id": 2, "items.monster_id": 1}},
+			LoadReport{Files: 10},
+			[]string{"items.monster_id (1), monster_traits.monster_id (2)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckIntegrity(tt.stats, tt.report)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("Expected %d problems, got %v", len(tt.want), problems)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("Expected problem %q to mention %q", problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSettleStagedRunsWithTwoSources(t *testing.T) {
+	stats := StagingStats{LiveMonsters: 100, StagedMonsters: 100}
+	pf2e := pendingRun{id: 1, source: Source{Name: DefaultSource}}
+	sf2e := pendingRun{id: 2, source: Source{Name: "sf2e"}}
+	loaded := func(run pendingRun, report LoadReport, err error) stagedRun {
+		return stagedRun{run: run, report: report, err: err, loaded: true}
+	}
+	tests := []struct {
+		name     string
+		results  []stagedRun
+		swap     bool
+		statuses []string
+	}{
+		{
+			"both loaded",
+			[]stagedRun{loaded(pf2e, LoadReport{Files: 90}, nil), loaded(sf2e, LoadReport{Files: 10}, nil)},
+			true,
+			[]string{SyncSucceeded, SyncSucceeded},
+		},
+		{
+			"one unchanged",
+			[]stagedRun{{run: pf2e, err: ErrUpstreamUnchanged}, loaded(sf2e, LoadReport{Files: 10}, nil)},
+			true,
+			[]string{SyncSkipped, SyncSucceeded},
+		},
+		{
+			"one failed to download",
+			[]stagedRun{loaded(pf2e, LoadReport{Files: 90}, nil), {run: sf2e, err: errors.New("download failed")}},
+			true,
+			[]string{SyncSucceeded, SyncFailed},
+		},
+		{
+			"one failed integrity checks",
+			[]stagedRun{loaded(pf2e, LoadReport{Files: 90}, nil), loaded(sf2e, LoadReport{Files: 10, Failed: 5}, nil)},
+			false,
+			[]string{SyncRejected, SyncRejected},
+		},
+		{
+			"one failed while loading",
+			[]stagedRun{loaded(pf2e, LoadReport{Files: 90}, errors.New("connection lost")), loaded(sf2e, LoadReport{Files: 10}, nil)},
+			false,
+			[]string{SyncFailed, SyncRejected},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if swap := settleStagedRuns(tt.results, stats); swap != tt.swap {
+				t.Errorf("Expected swap %v, got %v", tt.swap, swap)
+			}
+			for i, result := range tt.results {
+				if status := SyncRunStatus(result.err); status != tt.statuses[i] {
+					t.Errorf("%s: expected status %s, got %s (%v)", result.run.source.Name, tt.statuses[i], status, result.err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/tidwall/gjson"
+)
+
+// SyncDocument identifies the Foundry file a record was loaded from. The
+// FoundryID and PackPath pair is the natural key used to update a record in
+// place on later syncs, within the data source named by Source; ContentHash
+// lets unchanged files be skipped.
+type SyncDocument struct {
+	Path        string
+	FoundryID   string
+	PackPath    string
+	ContentHash string
+	SeenAt      time.Time
+	Source      string
+}
+
+// NewSyncDocument describes the file at filePath as a document of
+// DefaultSource. Documents without an _id fall back to their file name so
+// they still have a stable key.
+func NewSyncDocument(filePath string, data []byte, seenAt time.Time) SyncDocument {
+	sum := sha256.Sum256(data)
+	id := gjson.GetBytes(data, "_id").String()
+	if id == "" {
+		id = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	return SyncDocument{
+		Path:        filePath,
+		FoundryID:   id,
+		PackPath:    PackPath(filePath),
+		ContentHash: hex.EncodeToString(sum[:]),
+		SeenAt:      seenAt,
+		Source:      DefaultSource,
+	}
+}
+
+// PackPath returns the directory of a file relative to the Foundry packs
+// folder, e.g. "age-of-ashes-bestiary/book-1-hellknight-hill". Files outside a
+// packs folder use their directory as given.
+func PackPath(filePath string) string {
+	dir := path.Dir(filepath.ToSlash(filePath))
+	parts := strings.Split(dir, "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i] == "packs" {
+			return strings.Join(parts[i+1:], "/")
+		}
+	}
+	return dir
+}
+
+func NewTimestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
+// documentState is how a document compares with what is already stored.
+type documentState int
+
+const (
+	documentNew documentState = iota
+	documentChanged
+	documentUnchanged
+)
+
+// monsterSyncState compares doc with the stored monster. Unchanged monsters
+// are marked as seen so they are not retired.
+func monsterSyncState(ctx context.Context, queries *writeMonsters.Queries, doc SyncDocument) (documentState, error) {
+	state, err := queries.GetMonsterSyncState(ctx, writeMonsters.GetMonsterSyncStateParams{
+		FoundryID: NewText(doc.FoundryID),
+		PackPath:  NewText(doc.PackPath),
+		Source:    doc.Source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return documentNew, nil
+	}
+	if err != nil {
+		return documentNew, fmt.Errorf("failed to look up monster %s %w", doc.FoundryID, err)
+	}
+	if state.ContentHash.String != doc.ContentHash || state.RetiredAt.Valid {
+		return documentChanged, nil
+	}
+	err = queries.MarkMonsterSeen(ctx, writeMonsters.MarkMonsterSeenParams{ID: state.ID, LastSeenAt: NewTimestamptz(doc.SeenAt)})
+	if err != nil {
+		return documentChanged, fmt.Errorf("failed to mark monster %d as seen %w", state.ID, err)
+	}
+	return documentUnchanged, nil
+}
+
+// hazardSyncState is monsterSyncState for hazards.
+func hazardSyncState(ctx context.Context, queries *writeMonsters.Queries, doc SyncDocument) (documentState, error) {
+	state, err := queries.GetHazardSyncState(ctx, writeMonsters.GetHazardSyncStateParams{
+		FoundryID: NewText(doc.FoundryID),
+		PackPath:  NewText(doc.PackPath),
+		Source:    doc.Source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return documentNew, nil
+	}
+	if err != nil {
+		return documentNew, fmt.Errorf("failed to look up hazard %s %w", doc.FoundryID, err)
+	}
+	if state.ContentHash.String != doc.ContentHash || state.RetiredAt.Valid {
+		return documentChanged, nil
+	}
+	err = queries.MarkHazardSeen(ctx, writeMonsters.MarkHazardSeenParams{ID: state.ID, LastSeenAt: NewTimestamptz(doc.SeenAt)})
+	if err != nil {
+		return documentChanged, fmt.Errorf("failed to mark hazard %d as seen %w", state.ID, err)
+	}
+	return documentUnchanged, nil
+}
+
+// RetireUnseen marks every monster and hazard of the data source named source
+// not seen since the sync that started at since as retired. Only call it
+// after a sync that read every file of the source.
+func RetireUnseen(ctx context.Context, cfg config.Config, source string, since time.Time) (int64, int64, error) {
+	queries := writeMonsters.New(cfg.DBPool)
+	monsters, err := queries.RetireUnseenMonsters(ctx, writeMonsters.RetireUnseenMonstersParams{LastSeenAt: NewTimestamptz(since), Source: source})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to retire monsters %w", err)
+	}
+	hazards, err := queries.RetireUnseenHazards(ctx, writeMonsters.RetireUnseenHazardsParams{LastSeenAt: NewTimestamptz(since), Source: source})
+	if err != nil {
+		return monsters, 0, fmt.Errorf("failed to retire hazards %w", err)
+	}
+	// Files removed upstream are forgotten too, so if they come back they
+	// are parsed rather than skipped as unchanged.
+	if _, err := queries.DeleteUnseenSourceFiles(ctx, writeMonsters.DeleteUnseenSourceFilesParams{LastSeenAt: NewTimestamptz(since), Source: source}); err != nil {
+		return monsters, hazards, fmt.Errorf("failed to forget removed source files %w", err)
+	}
+	return monsters, hazards, nil
+}
+
+// stampDataVersion records source as the data version of every monster and
+// hazard of the data source named name seen by the sync that started at
+// seenAt, unchanged ones included, and of that data source as a whole.
+func stampDataVersion(ctx context.Context, cfg config.Config, name string, source SyncSource, seenAt time.Time) error {
+	tx, err := cfg.DBPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := writeMonsters.New(tx)
+	version := NewText(source.DataVersion())
+	seen := NewTimestamptz(seenAt)
+	if _, err := queries.StampMonstersDataVersion(ctx, writeMonsters.StampMonstersDataVersionParams{DataVersion: version, SeenAt: seen, Source: name}); err != nil {
+		return fmt.Errorf("failed to stamp monsters with the data version %w", err)
+	}
+	if _, err := queries.StampHazardsDataVersion(ctx, writeMonsters.StampHazardsDataVersionParams{DataVersion: version, SeenAt: seen, Source: name}); err != nil {
+		return fmt.Errorf("failed to stamp hazards with the data version %w", err)
+	}
+	err = queries.ReplaceDataVersion(ctx, writeMonsters.ReplaceDataVersionParams{
+		Source:        name,
+		Release:       pgtype.Text{String: source.Release, Valid: source.Release != ""},
+		SourceVersion: source.Version,
+		LoadedAt:      seen,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record the data version %w", err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+	"github.com/Burtcam/encounter-builder-backend/schema"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Status of a row in sync_runs. A rejected run loaded its data but failed the
+// integrity checks, so the live data was left in place. A skipped run found
+// the upstream data unchanged and loaded nothing.
+const (
+	SyncRunning   = "running"
+	SyncSucceeded = "succeeded"
+	SyncFailed    = "failed"
+	SyncRejected  = "rejected"
+	SyncSkipped   = "skipped"
+)
+
+// EnsureOpsTables creates the operational tables in the public schema if they
+// do not exist yet, and migrates ones created by an earlier release.
+func EnsureOpsTables(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	// The pool searches the bestiary first; these tables must not be created
+	// there or a swap would take them with it.
+	if _, err := tx.Exec(ctx, "SET LOCAL search_path TO public"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, schema.OpsDDL); err != nil {
+		return fmt.Errorf("failed to create operational tables %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// SourceVersion names the data a sync loaded. GitHub archives unpack into a
+// single owner-repo-sha directory, so its name identifies the commit.
+func SourceVersion(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var version string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if version != "" {
+			return ""
+		}
+		version = entry.Name()
+	}
+	return version
+}
+
+// SyncRunStatus picks the status recorded for a run that ended with err.
+func SyncRunStatus(err error) string {
+	switch {
+	case err == nil:
+		return SyncSucceeded
+	case errors.Is(err, ErrIntegrityCheck):
+		return SyncRejected
+	case errors.Is(err, ErrUpstreamUnchanged):
+		return SyncSkipped
+	}
+	return SyncFailed
+}
+
+// FinishSyncRun records the outcome of a run, the files that failed and what
+// the parser quarantined.
+func FinishSyncRun(ctx context.Context, cfg config.Config, id int32, source SyncSource, report LoadReport, runErr error) error {
+	tx, err := cfg.DBPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := writeMonsters.New(tx)
+	var message pgtype.Text
+	if runErr != nil && !errors.Is(runErr, ErrUpstreamUnchanged) {
+		message = NewText(runErr.Error())
+	}
+	err = queries.FinishSyncRun(ctx, writeMonsters.FinishSyncRunParams{
+		ID:              id,
+		FinishedAt:      NewTimestamptz(time.Now()),
+		Status:          SyncRunStatus(runErr),
+		SourceVersion:   NewText(source.Version),
+		Release:         pgtype.Text{String: source.Release, Valid: source.Release != ""},
+		ArchiveChecksum: NewText(source.Checksum),
+		PackFilter:      source.Packs,
+		FilesSeen:       int32(report.Files),
+		FilesParsed:     int32(report.Parsed),
+		RecordsInserted: int32(report.Inserted),
+		RecordsUpdated:  int32(report.Updated),
+		FilesSkipped:    int32(report.Skipped),
+		FilesFailed:     int32(report.Failed),
+		Error:           message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record sync run %d %w", id, err)
+	}
+	if len(report.Failures) > 0 {
+		failures := make([]writeMonsters.CopySyncFailuresParams, len(report.Failures))
+		for i, failure := range report.Failures {
+			failures[i] = writeMonsters.CopySyncFailuresParams{RunID: id, Path: failure.Path, Error: failure.Err.Error()}
+		}
+		if _, err := queries.CopySyncFailures(ctx, failures); err != nil {
+			return fmt.Errorf("failed to record failures for sync run %d %w", id, err)
+		}
+	}
+	if len(report.Unclassified) > 0 {
+		items := make([]writeMonsters.CopyQuarantinedItemsParams, len(report.Unclassified))
+		for i, item := range report.Unclassified {
+			items[i] = writeMonsters.CopyQuarantinedItemsParams{
+				RunID:    id,
+				Path:     item.Path,
+				ItemName: NewText(item.Item),
+				Reason:   item.Reason,
+				Raw:      item.Raw,
+			}
+		}
+		if _, err := queries.CopyQuarantinedItems(ctx, items); err != nil {
+			return fmt.Errorf("failed to record quarantined items for sync run %d %w", id, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncRunStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, SyncSucceeded},
+		{fmt.Errorf("staged data failed checks %w", ErrIntegrityCheck), SyncRejected},
+		{errors.New("download failed"), SyncFailed},
+	}
+	for _, tt := range tests {
+		if got := SyncRunStatus(tt.err); got != tt.want {
+			t.Errorf("SyncRunStatus(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSourceVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "foundryvtt-pf2e-abc123"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pax_global_header"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := SourceVersion(dir); got != "foundryvtt-pf2e-abc123" {
+		t.Errorf("Expected 'foundryvtt-pf2e-abc123', got '%s'", got)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "other"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := SourceVersion(dir); got != "" {
+		t.Errorf("Expected no version with two directories, got '%s'", got)
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPackPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"files/foundryvtt-pf2e-4cbdaa3/packs/age-of-ashes-bestiary/book-1-hellknight-hill/town-hall-fire.json", "age-of-ashes-bestiary/book-1-hellknight-hill"},
+		{"files/foundryvtt-pf2e-4cbdaa3/packs/pathfinder-bestiary/goblin-warrior.json", "pathfinder-bestiary"},
+		{"fixtures/goblin-warrior.json", "fixtures"},
+	}
+	for _, test := range tests {
+		if result := PackPath(test.path); result != test.expected {
+			t.Errorf("PackPath(%q) = %q; want %q", test.path, result, test.expected)
+		}
+	}
+}
+
+func TestNewSyncDocument(t *testing.T) {
+	seenAt := time.Date(2026, 1, 6, 3, 0, 0, 0, time.UTC)
+	data := []byte(`{"_id": "abc123", "name": "Goblin Warrior", "type": "npc"}`)
+	doc := NewSyncDocument("files/x/packs/pathfinder-bestiary/goblin-warrior.json", data, seenAt)
+	if doc.FoundryID != "abc123" || doc.PackPath != "pathfinder-bestiary" || !doc.SeenAt.Equal(seenAt) {
+		t.Errorf("Unexpected document key %+v", doc)
+	}
+	if len(doc.ContentHash) != 64 {
+		t.Errorf("Expected a sha256 hex digest, got %q", doc.ContentHash)
+	}
+
+	same := NewSyncDocument("other/place.json", data, seenAt)
+	if same.ContentHash != doc.ContentHash {
+		t.Errorf("Expected identical content to hash the same")
+	}
+	changed := NewSyncDocument("other/place.json", append(data, ' '), seenAt)
+	if changed.ContentHash == doc.ContentHash {
+		t.Errorf("Expected changed content to hash differently")
+	}
+
+	noID := NewSyncDocument("files/x/packs/pathfinder-bestiary/goblin-warrior.json", []byte(`{"type": "npc"}`), seenAt)
+	if noID.FoundryID != "goblin-warrior" {
+		t.Errorf("Expected file name fallback, got %q", noID.FoundryID)
+	}
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+)
+
+// Creature templates from GM Core: elite and weak creatures are the same
+// stat block shifted by +2 or -2 with a level and HP change.
+const (
+	TemplateElite = "elite"
+	TemplateWeak  = "weak"
+)
+
+var ErrInvalidTemplate = errors.New("template must be elite or weak")
+
+// TemplateLevel returns the level of a creature after the template is
+// applied. An empty template leaves the level alone.
+func TemplateLevel(level int, template string) (int, error) {
+	switch template {
+	case "":
+		return level, nil
+	case TemplateElite:
+		if level <= 0 {
+			return level + 2, nil
+		}
+		return level + 1, nil
+	case TemplateWeak:
+		if level == 1 {
+			return level - 2, nil
+		}
+		return level - 1, nil
+	}
+	return 0, ErrInvalidTemplate
+}
+
+// templateHpAdjustment is the HP change for a creature of the given starting
+// level, GM Core tables 2-1 and 2-2.
+func templateHpAdjustment(level int, template string) int {
+	if template == TemplateElite {
+		switch {
+		case level <= 1:
+			return 10
+		case level <= 4:
+			return 15
+		case level <= 19:
+			return 20
+		default:
+			return 30
+		}
+	}
+	switch {
+	case level <= 2:
+		return -10
+	case level <= 5:
+		return -15
+	case level <= 20:
+		return -20
+	default:
+		return -30
+	}
+}
+
+// ApplyTemplate returns a copy of monster with the elite or weak template
+// applied. AC, saves, perception, skills, strike bonuses, spell DCs and
+// spell attack modifiers move by 2; the first damage block of each strike
+// moves by 2; level and HP follow the GM Core tables.
+func ApplyTemplate(monster structs.Monster, template string) (structs.Monster, error) {
+	level, err := TemplateLevel(monster.Level, template)
+	if err != nil {
+		return monster, err
+	}
+	if template == "" {
+		return monster, nil
+	}
+	delta := 2
+	if template == TemplateWeak {
+		delta = -2
+	}
+
+	adjusted := monster
+	adjusted.Template = template
+	adjusted.Level = level
+	adjusted.HP.Value = max(monster.HP.Value+templateHpAdjustment(monster.Level, template), 1)
+	adjusted.AClass.Value += delta
+	adjusted.Saves.Fort += delta
+	adjusted.Saves.Ref += delta
+	adjusted.Saves.Will += delta
+	adjusted.Perception.Mod = adjustNumericString(monster.Perception.Mod, delta)
+
+	adjusted.Skills = make([]structs.Skill, len(monster.Skills))
+	for i, skill := range monster.Skills {
+		skill.Value += delta
+		specials := make([]structs.SkillSpecial, len(skill.Specials))
+		for j, special := range skill.Specials {
+			special.Value += delta
+			specials[j] = special
+		}
+		skill.Specials = specials
+		adjusted.Skills[i] = skill
+	}
+	adjusted.Melees = adjustAttacks(monster.Melees, delta)
+	adjusted.Ranged = adjustAttacks(monster.Ranged, delta)
+
+	casting := &adjusted.SpellCasting
+	casting.FocusSpellCasting = make([]structs.FocusSpellCasting, len(monster.SpellCasting.FocusSpellCasting))
+	for i, block := range monster.SpellCasting.FocusSpellCasting {
+		block.DC += delta
+		block.Mod = adjustNumericString(block.Mod, delta)
+		casting.FocusSpellCasting[i] = block
+	}
+	casting.InnateSpellCasting = make([]structs.InnateSpellCasting, len(monster.SpellCasting.InnateSpellCasting))
+	for i, block := range monster.SpellCasting.InnateSpellCasting {
+		block.DC += delta
+		block.Mod = adjustNumericString(block.Mod, delta)
+		casting.InnateSpellCasting[i] = block
+	}
+	casting.PreparedSpellCasting = make([]structs.PreparedSpellCasting, len(monster.SpellCasting.PreparedSpellCasting))
+	for i, block := range monster.SpellCasting.PreparedSpellCasting {
+		block.DC += delta
+		block.Mod = adjustNumericString(block.Mod, delta)
+		casting.PreparedSpellCasting[i] = block
+	}
+	casting.SpontaneousSpellCasting = make([]structs.SpontaneousSpellCasting, len(monster.SpellCasting.SpontaneousSpellCasting))
+	for i, block := range monster.SpellCasting.SpontaneousSpellCasting {
+		block.DC += delta
+		block.Mod = adjustNumericString(block.Mod, delta)
+		casting.SpontaneousSpellCasting[i] = block
+	}
+	return adjusted, nil
+}
+
+func adjustAttacks(attacks []structs.Attack, delta int) []structs.Attack {
+	if attacks == nil {
+		return nil
+	}
+	adjusted := make([]structs.Attack, len(attacks))
+	for i, attack := range attacks {
+		attack.ToHitBonus = adjustNumericString(attack.ToHitBonus, delta)
+		blocks := make([]structs.DamageBlock, len(attack.DamageBlocks))
+		copy(blocks, attack.DamageBlocks)
+		if len(blocks) > 0 {
+			blocks[0].DamageRoll = AdjustDamageRoll(blocks[0].DamageRoll, delta)
+		}
+		attack.DamageBlocks = blocks
+		adjusted[i] = attack
+	}
+	return adjusted
+}
+
+// adjustNumericString shifts a number stored as text. Anything that is not a
+// plain integer is returned unchanged.
+func adjustNumericString(value string, delta int) string {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return value
+	}
+	return strconv.Itoa(n + delta)
+}
+
+var damageRollPattern = regexp.MustCompile(`^\s*(\d+d\d+)?\s*([+-]?\s*\d+)?\s*$`)
+
+// AdjustDamageRoll adds delta to the flat modifier of a dice expression such
+// as "2d8+7" or "1d6". Rolls it cannot parse are returned unchanged.
+func AdjustDamageRoll(roll string, delta int) string {
+	match := damageRollPattern.FindStringSubmatch(roll)
+	if match == nil || (match[1] == "" && match[2] == "") {
+		return roll
+	}
+	modifier := 0
+	if match[2] != "" {
+		n, err := strconv.Atoi(strings.ReplaceAll(match[2], " ", ""))
+		if err != nil {
+			return roll
+		}
+		modifier = n
+	}
+	modifier += delta
+	if match[1] == "" {
+		return strconv.Itoa(modifier)
+	}
+	switch {
+	case modifier > 0:
+		return fmt.Sprintf("%s+%d", match[1], modifier)
+	case modifier < 0:
+		return fmt.Sprintf("%s%d", match[1], modifier)
+	}
+	return match[1]
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Burtcam/encounter-builder-backend/structs"
+	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
+)
+
+func TestTemplateLevel(t *testing.T) {
+	tests := []struct {
+		level    int
+		template string
+		expected int
+	}{
+		{-1, TemplateElite, 1},
+		{0, TemplateElite, 2},
+		{1, TemplateElite, 2},
+		{10, TemplateElite, 11},
+		{1, TemplateWeak, -1},
+		{2, TemplateWeak, 1},
+		{10, TemplateWeak, 9},
+		{5, "", 5},
+	}
+	for _, test := range tests {
+		result, err := TemplateLevel(test.level, test.template)
+		if err != nil {
+			t.Errorf("TemplateLevel(%d, %q) unexpected error: %v", test.level, test.template, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("TemplateLevel(%d, %q) = %d; want %d", test.level, test.template, result, test.expected)
+		}
+	}
+	if _, err := TemplateLevel(1, "mighty"); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected ErrInvalidTemplate, got %v", err)
+	}
+}
+
+func TestAdjustDamageRoll(t *testing.T) {
+	tests := []struct {
+		roll     string
+		delta    int
+		expected string
+	}{
+		{"2d8+7", 2, "2d8+9"},
+		{"2d8+7", -2, "2d8+5"},
+		{"1d6", 2, "1d6+2"},
+		{"1d6", -2, "1d6-2"},
+		{"1d4+2", -2, "1d4"},
+		{"2d6-1", -2, "2d6-3"},
+		{"5", 2, "7"},
+		{"(@actor.level)d6", 2, "(@actor.level)d6"},
+	}
+	for _, test := range tests {
+		if result := AdjustDamageRoll(test.roll, test.delta); result != test.expected {
+			t.Errorf("AdjustDamageRoll(%q, %d) = %q; want %q", test.roll, test.delta, result, test.expected)
+		}
+	}
+}
+
+func testTemplateMonster() structs.Monster {
+	return structs.Monster{
+		Name:       "Ogre Warrior",
+		Level:      3,
+		AClass:     structs.AC{Value: 17},
+		HP:         structs.HP{Value: 50},
+		Saves:      structs.Saves{Fort: 11, Ref: 6, Will: 5},
+		Perception: structs.Perception{Mod: "7"},
+		Skills: []structs.Skill{
+			{Name: "athletics", Value: 12, Specials: []structs.SkillSpecial{{Value: 14, Label: "shove"}}},
+		},
+		Melees: []structs.Attack{
+			{Name: "Ogre Hook", ToHitBonus: "12", DamageBlocks: []structs.DamageBlock{
+				{DamageRoll: "1d10+7", DamageType: "piercing"},
+				{DamageRoll: "1d6", DamageType: "fire"},
+			}},
+		},
+		SpellCasting: structs.SpellCasting{
+			InnateSpellCasting: []structs.InnateSpellCasting{{DC: 18, Mod: "10"}},
+		},
+	}
+}
+
+func TestApplyTemplateElite(t *testing.T) {
+	original := testTemplateMonster()
+	elite, err := ApplyTemplate(original, TemplateElite)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elite.Level != 4 || elite.Template != TemplateElite {
+		t.Errorf("Expected elite level 4, got %d (%q)", elite.Level, elite.Template)
+	}
+	if elite.HP.Value != 65 {
+		t.Errorf("Expected HP 65, got %d", elite.HP.Value)
+	}
+	if elite.AClass.Value != 19 {
+		t.Errorf("Expected AC 19, got %d", elite.AClass.Value)
+	}
+	if elite.Saves.Fort != 13 || elite.Saves.Ref != 8 || elite.Saves.Will != 7 {
+		t.Errorf("Expected saves 13/8/7, got %d/%d/%d", elite.Saves.Fort, elite.Saves.Ref, elite.Saves.Will)
+	}
+	if elite.Perception.Mod != "9" {
+		t.Errorf("Expected perception 9, got %s", elite.Perception.Mod)
+	}
+	if elite.Skills[0].Value != 14 || elite.Skills[0].Specials[0].Value != 16 {
+		t.Errorf("Expected athletics 14 (16 special), got %d (%d)", elite.Skills[0].Value, elite.Skills[0].Specials[0].Value)
+	}
+	hook := elite.Melees[0]
+	if hook.ToHitBonus != "14" {
+		t.Errorf("Expected to hit 14, got %s", hook.ToHitBonus)
+	}
+	if hook.DamageBlocks[0].DamageRoll != "1d10+9" {
+		t.Errorf("Expected damage 1d10+9, got %s", hook.DamageBlocks[0].DamageRoll)
+	}
+	if hook.DamageBlocks[1].DamageRoll != "1d6" {
+		t.Errorf("Expected additional damage unchanged, got %s", hook.DamageBlocks[1].DamageRoll)
+	}
+	innate := elite.SpellCasting.InnateSpellCasting[0]
+	if innate.DC != 20 || innate.Mod != "12" {
+		t.Errorf("Expected spell DC 20 mod 12, got %d %s", innate.DC, innate.Mod)
+	}
+
+	if original.Melees[0].DamageBlocks[0].DamageRoll != "1d10+7" || original.Skills[0].Value != 12 {
+		t.Errorf("ApplyTemplate modified the original monster")
+	}
+}
+
+func TestApplyTemplateWeak(t *testing.T) {
+	weak, err := ApplyTemplate(testTemplateMonster(), TemplateWeak)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weak.Level != 2 {
+		t.Errorf("Expected weak level 2, got %d", weak.Level)
+	}
+	if weak.HP.Value != 35 {
+		t.Errorf("Expected HP 35, got %d", weak.HP.Value)
+	}
+	if weak.AClass.Value != 15 {
+		t.Errorf("Expected AC 15, got %d", weak.AClass.Value)
+	}
+	if weak.Melees[0].DamageBlocks[0].DamageRoll != "1d10+5" {
+		t.Errorf("Expected damage 1d10+5, got %s", weak.Melees[0].DamageBlocks[0].DamageRoll)
+	}
+}
+
+func TestEvaluateEncounterTemplates(t *testing.T) {
+	lookup := fakeLevelLookup{rows: []writeMonsters.GetMonsterLevelsByIDsRow{
+		{ID: 1, Name: "Goblin Warrior", Level: NewInt4(-1)},
+	}}
+	party := NewParty(1, 4)
+	eval, err := EvaluateEncounter(context.Background(), lookup, party, []structs.EncounterEntry{
+		{MonsterID: 1, Count: 1, Template: TemplateElite},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if eval.Creatures[0].Level != 1 || eval.TotalXP != 40 {
+		t.Errorf("Expected elite goblin at level 1 worth 40 XP, got level %d worth %d", eval.Creatures[0].Level, eval.TotalXP)
+	}
+
+	_, err = EvaluateEncounter(context.Background(), lookup, party, []structs.EncounterEntry{
+		{MonsterID: 1, Count: 1, Template: "mighty"},
+	})
+	if !errors.Is(err, ErrInvalidEncounter) {
+		t.Errorf("Expected ErrInvalidEncounter, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Burtcam/encounter-builder-backend/config"
+)
+
+// ErrUpstreamUnchanged ends a sync that found the same upstream data as the
+// last successful one. Nothing is loaded and the run is recorded as skipped.
+var ErrUpstreamUnchanged = errors.New("upstream data is unchanged since the last successful sync")
+
+// SyncSource identifies the upstream data a sync loaded.
+type SyncSource struct {
+	// Version is the upstream commit SHA. When the commit cannot be resolved
+	// it is the name of the directory the archive unpacked into.
+	Version string
+	// Release is the tag SYNC_RELEASE selected; empty when REPO_URL was
+	// followed as given or the data was imported.
+	Release string
+	// Checksum is the hex SHA-256 of the archive loaded; empty when the
+	// files were imported from a directory.
+	Checksum string
+	// Packs are the pack patterns an import was restricted to. Empty for a
+	// sync that loaded every file.
+	Packs []string
+}
+
+// DataVersion is what the records a sync loads are stamped with: the release
+// tag when there is one, otherwise Version.
+func (s SyncSource) DataVersion() string {
+	if s.Release != "" {
+		return s.Release
+	}
+	return s.Version
+}
+
+// unchangedSince reports whether s is the same data last loaded. The archive
+// checksum is only compared when the commit could not be resolved.
+func (s SyncSource) unchangedSince(last SyncSource, commitResolved bool) bool {
+	if commitResolved {
+		return s.Version != "" && s.Version == last.Version
+	}
+	return s.Checksum != "" && s.Checksum == last.Checksum
+}
+
+var githubTarballURL = regexp.MustCompile(`^https://api\.github\.com/repos/([^/]+)/([^/]+)/tarball(?:/(.+))?$`)
+
+// upstreamCommitURL returns the GitHub API URL of the commit a tarball URL
+// is built from. Only api.github.com tarball URLs can be resolved.
+func upstreamCommitURL(repoURL string) (string, bool) {
+	m := githubTarballURL.FindStringSubmatch(repoURL)
+	if m == nil {
+		return "", false
+	}
+	ref := m[3]
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s", m[1], m[2], ref), true
+}
+
+// ResolveUpstreamCommit returns the commit SHA that REPO_URL currently points
+// at, without downloading the archive. It returns an empty string when
+// REPO_URL is not a GitHub API tarball URL.
+func ResolveUpstreamCommit(ctx context.Context, cfg config.Config) (string, error) {
+	url, ok := upstreamCommitURL(cfg.REPO_URL)
+	if !ok {
+		return "", nil
+	}
+	return fetchCommitSHA(ctx, http.DefaultClient, url, cfg.GH_TOKEN)
+}
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+func fetchCommitSHA(ctx context.Context, client *http.Client, url string, token string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "MyGoClient/1.0")
+	req.Header.Set("Accept", "application/vnd.github.sha")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("resolving upstream commit: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 128))
+	if err != nil {
+		return "", fmt.Errorf("resolving upstream commit: %w", err)
+	}
+	sha := strings.TrimSpace(string(body))
+	if !commitSHA.MatchString(sha) {
+		return "", fmt.Errorf("resolving upstream commit: unexpected response %q", sha)
+	}
+	return sha, nil
+}
+
+// fileChecksum returns the hex SHA-256 of the file at path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpstreamCommitURL(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		want    string
+		ok      bool
+	}{
+		{"https://api.github.com/repos/foundryvtt/pf2e/tarball/master", "https://api.github.com/repos/foundryvtt/pf2e/commits/master", true},
+		{"https://api.github.com/repos/foundryvtt/pf2e/tarball", "https://api.github.com/repos/foundryvtt/pf2e/commits/HEAD", true},
+		{"https://api.github.com/repos/foundryvtt/pf2e/tarball/release/v6", "https://api.github.com/repos/foundryvtt/pf2e/commits/release/v6", true},
+		{"https://example.com/pf2e.tar.gz", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := upstreamCommitURL(tt.repoURL)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("upstreamCommitURL(%q) = %q, %v; want %q, %v", tt.repoURL, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFetchCommitSHA(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/vnd.github.sha" {
+			http.Error(w, "wrong accept header", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte(sha + "\n"))
+		case "/json":
+			w.Write([]byte(`{"sha": "` + sha + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	got, err := fetchCommitSHA(context.Background(), server.Client(), server.URL+"/ok", "")
+	if err != nil || got != sha {
+		t.Errorf("Expected %s, got %q (%v)", sha, got, err)
+	}
+	if _, err := fetchCommitSHA(context.Background(), server.Client(), server.URL+"/json", ""); err == nil {
+		t.Error("Expected an error for a body that is not a bare SHA")
+	}
+	if _, err := fetchCommitSHA(context.Background(), server.Client(), server.URL+"/missing", ""); err == nil {
+		t.Error("Expected an error for a 404")
+	}
+}
+
+func TestSyncSourceUnchangedSince(t *testing.T) {
+	last := SyncSource{Version: "abc", Checksum: "123"}
+	tests := []struct {
+		name     string
+		source   SyncSource
+		resolved bool
+		want     bool
+	}{
+		{"same commit", SyncSource{Version: "abc"}, true, true},
+		{"new commit", SyncSource{Version: "def", Checksum: "123"}, true, false},
+		{"same archive", SyncSource{Checksum: "123"}, false, true},
+		{"new archive", SyncSource{Checksum: "456"}, false, false},
+		{"nothing to compare", SyncSource{}, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.source.unchangedSince(last, tt.resolved); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -1,15 +1,12 @@
 package utils
 
 import (
-	"archive/tar"
-	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,13 +14,12 @@ import (
 	"github.com/Burtcam/encounter-builder-backend/logger"
 	"github.com/Burtcam/encounter-builder-backend/structs"
 	"github.com/Burtcam/encounter-builder-backend/writeMonsters"
-	"github.com/google/uuid"
 
 	//"github.com/jackc/pgx/pgtype"
 
 	// "github.com/jackc/pgx/pgtype"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/robfig/cron/v3"
 	"github.com/tidwall/gjson"
 )
 
@@ -35,143 +31,84 @@ func NewInt4(value int) pgtype.Int4 {
 	return pgtype.Int4{Int32: int32(value), Valid: true}
 }
 
+// Base XP budget for a party of four, GM Core table 10-1.
+var threatBudgets = map[string]int{
+	"trivial":  40,
+	"low":      60,
+	"moderate": 80,
+	"severe":   120,
+	"extreme":  160,
+}
+
+// XP added or removed from the budget for each character above or below four.
+var characterAdjustments = map[string]int{
+	"trivial":  10,
+	"low":      20,
+	"moderate": 20,
+	"severe":   30,
+	"extreme":  40,
+}
+
+var (
+	ErrInvalidDifficulty = errors.New("failed likely due to the difficulty input being incorrect and not in the map")
+	ErrInvalidPartySize  = errors.New("pSize cannot be negative")
+)
+
 func GetXpBudget(difficulty string, pSize int) (int, error) {
 	difficulty = strings.ToLower(difficulty)
-	difficultyMap := make(map[string]int)
-	difficultyMap["trivial"] = 40
-	difficultyMap["low"] = 60
-	difficultyMap["moderate"] = 80
-	difficultyMap["severe"] = 120
-	difficultyMap["extreme"] = 160
-
-	levelAdjustment := make(map[string]int)
-	levelAdjustment["trivial"] = 10
-	levelAdjustment["low"] = 20
-	levelAdjustment["moderate"] = 20
-	levelAdjustment["severe"] = 30
-	levelAdjustment["extreme"] = 40
 	logger.Log.Info(fmt.Sprintf("%d", pSize))
-	value, exists := difficultyMap[difficulty]
+	value, exists := threatBudgets[difficulty]
 	if exists {
 		if pSize <= 0 {
-			return 0, errors.New("pSize cannot be negative")
+			return 0, ErrInvalidPartySize
 		}
 		if pSize == 4 {
 			logger.Log.Info("pSize found to be 4")
-			logger.Log.Debug(fmt.Sprintf("The value found for the given input is %d", difficultyMap[difficulty]))
+			logger.Log.Debug(fmt.Sprintf("The value found for the given input is %d", threatBudgets[difficulty]))
 			return value, nil
 		}
 		if pSize > 4 {
-			budget := value + (levelAdjustment[difficulty] * (pSize - 4))
+			budget := value + (characterAdjustments[difficulty] * (pSize - 4))
 			return budget, nil
 		}
 		if pSize < 4 {
-			budget := value - (levelAdjustment[difficulty] * (4 - pSize))
+			budget := value - (characterAdjustments[difficulty] * (4 - pSize))
 			return budget, nil
 		}
 	} else {
-		return 0, errors.New("failed likely due to the difficulty input being incorrect and not in the map")
+		return 0, ErrInvalidDifficulty
 	}
 	return 0, errors.New("unspecfied Error")
 }
 
-func GetMonstersInLevelRange(min int, max int) error {
-	// If
-	monsters := queries.GetMonstersByLevelRange
-
-	return nil
-}
-
-func GetRepoArchive(cfg config.Config) error {
-	client := &http.Client{}
-	// call to the repoUrl and get the archive downloaded.
-	req, err := http.NewRequest("GET", cfg.REPO_URL, nil)
-	if err != nil {
-		return fmt.Errorf("error creating request: %w", err)
-	}
-	req.Header.Set("User-Agent", "MyGoClient/1.0")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.GH_TOKEN))
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	// Execute the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	logger.Log.Info(fmt.Sprintf("Response Return code is: %s", resp.Status))
-	defer resp.Body.Close()
-
-	// Handle the response (assume we want to save it)
-	outFile, err := os.Create("repo_archive.tar.gz")
-	if err != nil {
-		return fmt.Errorf("error creating file: %w", err)
+// GetCharacterAdjustment returns the XP each character above or below four
+// adds to or removes from the budget for a difficulty.
+func GetCharacterAdjustment(difficulty string) (int, error) {
+	adjustment, exists := characterAdjustments[strings.ToLower(difficulty)]
+	if !exists {
+		return 0, ErrInvalidDifficulty
 	}
-	defer outFile.Close()
-
-	// Copy response body to the file
-	_, err = io.Copy(outFile, resp.Body)
-	if err != nil {
-		return fmt.Errorf("error saving archive: %w", err)
-	}
-
-	logger.Log.Info(fmt.Sprintln("Repository archive downloaded successfully!"))
-	return nil
+	return adjustment, nil
 }
-func extractTarball(tarFile string, destDir string) error {
-	// Open the tar file
-	file, err := os.Open(tarFile)
-	if err != nil {
-		return fmt.Errorf("failed to open tar file: %w", err)
-	}
-	defer file.Close()
 
-	gzipReader, err := gzip.NewReader(file)
-	if err != nil {
-		return err
+// GetRepoArchive downloads REPO_URL to SYNC_ARCHIVE_PATH and returns its hex
+// SHA-256. Server errors and rate limits are retried up to
+// SYNC_DOWNLOAD_ATTEMPTS times, resuming where the last attempt stopped, and
+// the whole download is abandoned after SYNC_DOWNLOAD_TIMEOUT. Nothing is
+// written to SYNC_ARCHIVE_PATH unless the download completes and matches
+// SYNC_ARCHIVE_SHA256 when it is set.
+func GetRepoArchive(ctx context.Context, cfg config.Config) (string, error) {
+	if cfg.SYNC_DOWNLOAD_TIMEOUT > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.SYNC_DOWNLOAD_TIMEOUT)
+		defer cancel()
 	}
-
-	defer gzipReader.Close()
-
-	// Create a tar reader
-	tarReader := tar.NewReader(gzipReader)
-
-	// Read each file inside the tar archive
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break // End of archive
-		}
-		if err != nil {
-			// logger.Log.Error("error reading tar file: %w", err.Error())
-			return err
-		}
-
-		// Determine the file path
-		filePath := destDir + "/" + header.Name
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			// Create directories
-			if err := os.MkdirAll(filePath, os.FileMode(header.Mode)); err != nil {
-				// logger.Log.Error(("error creating directory: %w", err.Error()))
-				return err
-			}
-		case tar.TypeReg:
-			// Extract regular files
-			outFile, err := os.Create(filePath)
-			if err != nil {
-				// logger.Log.Error(("error creating file: %w", err.Error()))
-				return err
-			}
-			defer outFile.Close()
-
-			// Copy the file contents from the archive
-			if _, err := io.Copy(outFile, tarReader); err != nil {
-				return fmt.Errorf("error writing file: %w", err)
-			}
-		}
+	checksum, err := newArchiveDownload(cfg).run(ctx)
+	if err != nil {
+		return "", err
 	}
-	return nil
+	logger.Log.Info("Repository archive downloaded", "sha256", checksum)
+	return checksum, nil
 }
 
 func GetListofJSON(dir string) ([]string, error) {
@@ -195,779 +132,529 @@ func GetListofJSON(dir string) ([]string, error) {
 }
 
 func ParseFoundJson(data string) (structs.Monster, error) {
+	monster, _, err := parseMonster(data)
+	return monster, err
+}
+
+// parseMonster reads an npc document and returns the embedded items that
+// could not be classified alongside the monster.
+func parseMonster(data string) (structs.Monster, []Unclassified, error) {
 	monster := ParseCoreData(string(data))
 	//Parse items and pass it just the items list then attach the return values to monster.
 	ItemsList := gjson.Get(string(data), "items")
 	var spells []structs.Spell
+	var unclassified []Unclassified
 	var err error
-	monster.FreeActions, monster.Actions, monster.Reactions, monster.Passives, monster.SpellCasting, spells, monster.Melees, monster.Ranged, monster.Inventory, err = ParseItems(ItemsList)
+	monster.FreeActions, monster.Actions, monster.Reactions, monster.Passives, monster.SpellCasting, spells, monster.Melees, monster.Ranged, monster.Inventory, unclassified, err = ParseItems(ItemsList)
 	if err != nil {
-		return monster, err
+		return monster, unclassified, err
 	}
 	AssignSpell(&spells, &monster.SpellCasting)
-	return monster, err
+	return monster, unclassified, nil
 }
 
-func PrepMonsterParams(monster structs.Monster) writeMonsters.InsertMonsterParams {
-
-	monsterParams := writeMonsters.InsertMonsterParams{
+func PrepMonsterParams(monster structs.Monster, doc SyncDocument) writeMonsters.UpsertMonsterParams {
 
+	monsterParams := writeMonsters.UpsertMonsterParams{
+		FoundryID:        NewText(doc.FoundryID),
+		PackPath:         NewText(doc.PackPath),
+		ContentHash:      NewText(doc.ContentHash),
+		LastSeenAt:       NewTimestamptz(doc.SeenAt),
 		Name:             monster.Name,
-		Level:            NewText(monster.Level),
+		Level:            NewInt4(monster.Level),
 		FocusPoints:      NewInt4(monster.FocusPoints),
 		TraitsRarity:     NewText(monster.Traits.Rarity),
 		TraitsSize:       NewText(monster.Traits.Size),
-		AttrStr:          NewText(monster.Attributes.Str),
-		AttrDex:          NewText(monster.Attributes.Dex),
-		AttrCon:          NewText(monster.Attributes.Con),
-		AttrWis:          NewText(monster.Attributes.Wis),
-		AttrInt:          NewText(monster.Attributes.Int),
-		AttrCha:          NewText(monster.Attributes.Cha),
-		SavesFort:        NewText(monster.Saves.Fort),
+		AttrStr:          NewInt4(monster.Attributes.Str),
+		AttrDex:          NewInt4(monster.Attributes.Dex),
+		AttrCon:          NewInt4(monster.Attributes.Con),
+		AttrWis:          NewInt4(monster.Attributes.Wis),
+		AttrInt:          NewInt4(monster.Attributes.Int),
+		AttrCha:          NewInt4(monster.Attributes.Cha),
+		SavesFort:        NewInt4(monster.Saves.Fort),
 		SavesFortDetail:  NewText(monster.Saves.FortDetail),
-		SavesRef:         NewText(monster.Saves.Ref),
+		SavesRef:         NewInt4(monster.Saves.Ref),
 		SavesRefDetail:   NewText(monster.Saves.RefDetail),
-		SavesWill:        NewText(monster.Saves.Will),
+		SavesWill:        NewInt4(monster.Saves.Will),
 		SavesWillDetail:  NewText(monster.Saves.WillDetail),
 		SavesException:   NewText(monster.Saves.Exception),
-		AcValue:          NewText(monster.AClass.Value),
+		AcValue:          NewInt4(monster.AClass.Value),
 		AcDetail:         NewText(monster.AClass.Detail),
 		HpValue:          NewInt4(monster.HP.Value),
 		HpDetail:         NewText(monster.HP.Detail),
 		PerceptionMod:    NewText(monster.Perception.Mod),
 		PerceptionDetail: NewText(monster.Perception.Detail),
+		Source:           doc.Source,
 	}
 	return monsterParams
 }
 
-func writeImmunites(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-
-	for i := 0; i < len(monster.Immunities); i++ {
-		err := queries.InsertMonsterImmunities(ctx, writeMonsters.InsertMonsterImmunitiesParams{
-			MonsterID: NewInt4(int(id)),
-			Immunity:  NewText(monster.Immunities[i]),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to insert immunity %s for monster ID %d: %w", monster.Immunities[i], id, err)
-		}
-		logger.Log.Info(fmt.Sprintf("Succesfully inserted immunity %s for monster ID %d", monster.Immunities[i], id))
-	}
-
-	return nil
-}
-
-func ProcessWeakAndResist(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-
-	for i := 0; i < len(monster.Weaknesses); i++ {
-		DamageModifierID, err := queries.InsertMonsterDamageModifier(ctx, writeMonsters.InsertMonsterDamageModifierParams{
-			MonsterID:        NewInt4(int(id)),
-			ModifierCategory: NewText("weakness"),
-			Value:            NewInt4(monster.Weaknesses[i].Value),
-			DamageType:       NewText(monster.Weaknesses[i].Type),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add damage modifier to DB %w", err)
-		}
-		// if exceptions len > 0
-		if len(monster.Weaknesses[i].Exceptions) > 0 {
-			for j := 0; j < len(monster.Weaknesses[i].Exceptions); j++ {
-				err = queries.InsertMonsterModifierExceptions(ctx, writeMonsters.InsertMonsterModifierExceptionsParams{
-					ModifierID: NewInt4(int(DamageModifierID)),
-					Exception:  NewText(monster.Weaknesses[i].Exceptions[j]),
-				})
-			}
-		}
-		if err != nil {
-			return fmt.Errorf("failed to add damage modifier to DB %w", err)
-		}
-		// if exceptions len > 0
-		if len(monster.Weaknesses[i].Double) > 0 {
-			for k := 0; k < len(monster.Weaknesses[i].Double); k++ {
-				err = queries.InsertMonsterModifierExceptions(ctx, writeMonsters.InsertMonsterModifierExceptionsParams{
-					ModifierID: NewInt4(int(DamageModifierID)),
-					Exception:  NewText(monster.Weaknesses[i].Double[k]),
-				})
-			}
-		}
-		if err != nil {
-			return fmt.Errorf("failed to add damage modifier to DB %w", err)
-		}
-	}
-	for i := 0; i < len(monster.Resistances); i++ {
-		DamageModifierID, err := queries.InsertMonsterDamageModifier(ctx, writeMonsters.InsertMonsterDamageModifierParams{
-			MonsterID:        NewInt4(int(id)),
-			ModifierCategory: NewText("resistance"),
-			Value:            NewInt4(monster.Resistances[i].Value),
-			DamageType:       NewText(monster.Resistances[i].Type),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to add damage modifier to DB Resistances  %w", err)
-		}
-		// if exceptions len > 0
-		if len(monster.Resistances[i].Exceptions) > 0 {
-			for j := 0; j < len(monster.Resistances[i].Exceptions); j++ {
-				err = queries.InsertMonsterModifierExceptions(ctx, writeMonsters.InsertMonsterModifierExceptionsParams{
-					ModifierID: NewInt4(int(DamageModifierID)),
-					Exception:  NewText(monster.Resistances[i].Exceptions[j]),
-				})
-			}
-		}
-		if err != nil {
-			return fmt.Errorf("failed to add damage modifier to DB Exceptions %w", err)
-		}
-		// if exceptions len > 0
-		if len(monster.Resistances[i].Double) > 0 {
-			for k := 0; k < len(monster.Resistances[i].Double); k++ {
-				err = queries.InsertMonsterModifierExceptions(ctx, writeMonsters.InsertMonsterModifierExceptionsParams{
-					ModifierID: NewInt4(int(DamageModifierID)),
-					Exception:  NewText(monster.Resistances[i].Double[k]),
-				})
-			}
-		}
-		if err != nil {
-			return fmt.Errorf("failed to add damage modifier to DB %w", err)
-		}
-	}
-	return nil
-}
-
-func ProcessLanguages(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := 0; i < len(monster.Languages); i++ {
-		err := queries.InsertMonsterLanguages(ctx, writeMonsters.InsertMonsterLanguagesParams{
-			MonsterID: NewInt4(int(id)),
-			Language:  NewText(monster.Languages[i]),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to write language %w", err)
-		}
+// LoadEachJSON loads one Foundry document. seenAt is the start of the sync
+// it belongs to; documents whose content has not changed since the last sync
+// are only marked as seen.
+func LoadEachJSON(cfg config.Config, path string, seenAt time.Time) error {
+	ctx := context.Background()
+	doc, err := loadDocument(ctx, cfg, path, seenAt)
+	if err != nil || doc == nil {
+		return err
 	}
-	return nil
-}
-
-func ProcessSenses(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := range len(monster.Senses) {
-		err := queries.InsertMonsterSenses(ctx, writeMonsters.InsertMonsterSensesParams{
-			MonsterID: NewInt4(int(id)),
-			Name:      NewText(monster.Senses[i].Name),
-			Range:     NewText(monster.Senses[i].Range),
-			Acuity:    NewText(monster.Senses[i].Acuity),
-			Detail:    NewText(monster.Senses[i].Detail),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to write sense to DB %w", err)
-		}
+	for _, item := range doc.Unclassified {
+		logger.Log.Warn("Could not classify document", "path", item.Path, "item", item.Item, "reason", item.Reason)
 	}
-	return nil
+	return doc.write(ctx, cfg)
 }
 
-func ProcessMovements(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := 0; i < len(monster.Movements); i++ {
-		err := queries.InsertMonsterMovements(ctx, writeMonsters.InsertMonsterMovementsParams{
-			MonsterID:    pgtype.Int4{Int32: id},
-			MovementType: NewText(monster.Movements[i].Type),
-			Speed:        NewText(monster.Movements[i].Speed),
-			Notes:        NewText(monster.Movements[i].Notes),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to write Movements to DB %w", err)
-		}
-	}
-	return nil
+// parsedDocument is a parsed Foundry document. At most one of Monster and
+// Hazard is set, and only when the document changed and needs writing.
+// Existing is true when the write replaces a record from an earlier sync.
+// Unclassified lists what the parser could not place: the whole document, or
+// items embedded in it.
+type parsedDocument struct {
+	Monster      *MonsterRecord
+	Hazard       *HazardRecord
+	Existing     bool
+	Unclassified []Unclassified
 }
 
-func ProcessSkills(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := 0; i < len(monster.Skills); i++ {
-		skillId, err := queries.InsertMonsterSkills(ctx, writeMonsters.InsertMonsterSkillsParams{
-			MonsterID: NewInt4(int(id)),
-			Name:      NewText(monster.Skills[i].Name),
-			Value:     NewInt4(monster.Skills[i].Value),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to write skill %w", err)
-		}
-		if len(monster.Skills[i].Specials) > 0 {
-			for j := 0; j < len(monster.Skills[i].Specials); j++ {
-				err := queries.InsertMonsterSkillSpecials(ctx, writeMonsters.InsertMonsterSkillSpecialsParams{
-					SkillID:    NewInt4(int(skillId)),
-					Value:      NewInt4(monster.Skills[i].Specials[j].Value),
-					Label:      NewText(monster.Skills[i].Specials[j].Label),
-					Predicates: monster.Skills[i].Specials[j].Predicates,
-				})
-				if err != nil {
-					return fmt.Errorf("unable to write skill specials %w", err)
-				}
-			}
-		}
-	}
-	return nil
+func (d *parsedDocument) stored() bool {
+	return d.Monster != nil || d.Hazard != nil
 }
 
-func ProcessAction(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := 0; i < len(monster.Actions); i++ {
-		actionId, err := queries.InsertMonsterAction(ctx, writeMonsters.InsertMonsterActionParams{
-			MonsterID:  NewInt4(int(id)),
-			ActionType: NewText("action"),
-			Name:       NewText(monster.Actions[i].Name),
-			Text:       NewText(monster.Actions[i].Text),
-			Actions:    NewText(monster.Actions[i].Actions),
-			Category:   NewText(monster.Actions[i].Category),
-			Rarity:     NewText(monster.Actions[i].Rarity),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to process Monster Action %w", err)
-		}
-		for j := 0; j < len(monster.Actions[i].Traits); j++ {
-			err := queries.InsertMonsterActionTraits(ctx, writeMonsters.InsertMonsterActionTraitsParams{
-				MonsterActionID: NewInt4(int(actionId)),
-				Trait:           NewText(monster.Actions[i].Traits[j]),
-			})
-			if err != nil {
-				return fmt.Errorf("unable to Process Traits for Actions %w", err)
-			}
-		}
+func (d *parsedDocument) write(ctx context.Context, cfg config.Config) error {
+	switch {
+	case d.Hazard != nil:
+		return WriteHazardToDb(d.Hazard.Hazard, d.Hazard.Doc, cfg)
+	case d.Monster != nil:
+		return WriteMonsterBatch(ctx, cfg, []MonsterRecord{*d.Monster})
 	}
 	return nil
 }
 
-func ProcessReaction(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := 0; i < len(monster.Reactions); i++ {
-		actionId, err := queries.InsertMonsterAction(ctx, writeMonsters.InsertMonsterActionParams{
-			MonsterID:  NewInt4(int(id)),
-			ActionType: NewText("reaction"),
-			Name:       NewText(monster.Reactions[i].Name),
-			Text:       NewText(monster.Reactions[i].Text),
-			Category:   NewText(monster.Reactions[i].Category),
-			Rarity:     NewText(monster.Reactions[i].Rarity),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to process Monster Reaction %w", err)
-		}
-		for j := 0; j < len(monster.Reactions[i].Traits); j++ {
-			err := queries.InsertMonsterActionTraits(ctx, writeMonsters.InsertMonsterActionTraitsParams{
-				MonsterActionID: NewInt4(int(actionId)),
-				Trait:           NewText(monster.Reactions[i].Traits[j]),
-			})
-			if err != nil {
-				return fmt.Errorf("unable to Process Traits for Reaction %w", err)
-			}
-		}
+// loadDocument reads and parses one Foundry document. Unchanged and
+// unsupported documents return nil, unless the document could not be
+// classified.
+func loadDocument(ctx context.Context, cfg config.Config, path string, seenAt time.Time) (*parsedDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Log.Error(err.Error())
+		return nil, err
 	}
-	return nil
+	return decodeDocument(ctx, cfg, NewSyncDocument(path, data, seenAt), data)
 }
 
-func ProcessPassive(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := 0; i < len(monster.Passives); i++ {
-		actionId, err := queries.InsertMonsterAction(ctx, writeMonsters.InsertMonsterActionParams{
-			MonsterID:  NewInt4(int(id)),
-			ActionType: NewText("passive"),
-			Name:       NewText(monster.Passives[i].Name),
-			Text:       NewText(monster.Passives[i].Text),
-			Category:   NewText(monster.Passives[i].Category),
-			Rarity:     NewText(monster.Passives[i].Rarity),
-			Dc:         NewText(monster.Passives[i].DC),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to process Monster Passive %w", err)
-		}
-		for j := 0; j < len(monster.Passives[i].Traits); j++ {
-			err := queries.InsertMonsterActionTraits(ctx, writeMonsters.InsertMonsterActionTraitsParams{
-				MonsterActionID: NewInt4(int(actionId)),
-				Trait:           NewText(monster.Passives[i].Traits[j]),
-			})
-			if err != nil {
-				return fmt.Errorf("unable to Process Traits for Passive %w", err)
-			}
-		}
+// decodeDocument is loadDocument for a file that has already been read.
+func decodeDocument(ctx context.Context, cfg config.Config, doc SyncDocument, data []byte) (*parsedDocument, error) {
+	path := doc.Path
+	if reason := classifyDocument(string(data)); reason != "" {
+		return &parsedDocument{Unclassified: []Unclassified{{Path: path, Reason: reason, Raw: string(data)}}}, nil
 	}
-	return nil
-}
 
-func ProcessAttacks(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := range len(monster.Melees) {
-		attackID, err := queries.InsertMonsterAttacks(ctx, writeMonsters.InsertMonsterAttacksParams{
-			MonsterID:           NewInt4(int(id)),
-			AttackCategory:      NewText("melee"),
-			Name:                NewText(monster.Melees[i].Name),
-			AttackType:          NewText(monster.Melees[i].Type),
-			ToHitBonus:          NewText(monster.Melees[i].ToHitBonus),
-			EffectsCustomString: NewText(monster.Melees[i].Effects.CustomString),
-			EffectsValues:       monster.Melees[i].Effects.Value,
-		})
+	switch gjson.Get(string(data), "type").String() {
+	case "npc":
+		state, err := monsterSyncState(ctx, writeMonsters.New(cfg.DBPool), doc)
 		if err != nil {
-			return fmt.Errorf("unable to write attack ID %w", err)
+			return nil, err
 		}
-		for j := range len(monster.Melees[i].DamageBlocks) {
-			err = queries.InsertMonsterAttackDamageBlock(ctx, writeMonsters.InsertMonsterAttackDamageBlockParams{
-				AttackID:   NewInt4(int(attackID)),
-				DamageRoll: NewText(monster.Melees[i].DamageBlocks[j].DamageRoll),
-				DamageType: NewText(monster.Melees[i].DamageBlocks[j].DamageType),
-			})
+		if state == documentUnchanged {
+			return nil, nil
 		}
+		monster, unclassified, err := parseMonster(string(data))
 		if err != nil {
-			return fmt.Errorf("unable to write damageblock %w", err)
+			return nil, err
 		}
-	}
-	for i := range len(monster.Ranged) {
-		attackID, err := queries.InsertMonsterAttacks(ctx, writeMonsters.InsertMonsterAttacksParams{
-			MonsterID:           pgtype.Int4{Int32: id},
-			AttackCategory:      pgtype.Text{String: "ranged"},
-			Name:                pgtype.Text{String: monster.Ranged[i].Name},
-			AttackType:          pgtype.Text{String: monster.Ranged[i].Type},
-			ToHitBonus:          pgtype.Text{String: monster.Ranged[i].ToHitBonus},
-			EffectsCustomString: pgtype.Text{String: monster.Ranged[i].Effects.CustomString},
-			EffectsValues:       monster.Ranged[i].Effects.Value,
-		})
+		return &parsedDocument{
+			Monster:      &MonsterRecord{Monster: monster, Doc: doc},
+			Existing:     state == documentChanged,
+			Unclassified: inDocument(path, unclassified),
+		}, nil
+	case "hazard":
+		state, err := hazardSyncState(ctx, writeMonsters.New(cfg.DBPool), doc)
 		if err != nil {
-			return fmt.Errorf("unable to write attack ID %w", err)
+			return nil, err
 		}
-		for j := range len(monster.Ranged[i].DamageBlocks) {
-			err = queries.InsertMonsterAttackDamageBlock(ctx, writeMonsters.InsertMonsterAttackDamageBlockParams{
-				AttackID:   pgtype.Int4{Int32: attackID},
-				DamageRoll: pgtype.Text{String: monster.Ranged[i].DamageBlocks[j].DamageRoll},
-				DamageType: pgtype.Text{String: monster.Ranged[i].DamageBlocks[j].DamageType},
-			})
+		if state == documentUnchanged {
+			return nil, nil
 		}
+		hazard, unclassified, err := parseHazard(string(data))
 		if err != nil {
-			return fmt.Errorf("unable to write damageblock %w", err)
+			return nil, err
 		}
+		return &parsedDocument{
+			Hazard:       &HazardRecord{Hazard: hazard, Doc: doc},
+			Existing:     state == documentChanged,
+			Unclassified: inDocument(path, unclassified),
+		}, nil
 	}
-	return nil
+
+	return nil, nil
 }
 
-func processSpellGeneric(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, spell structs.Spell) (string, error) {
-	spellId, err := queries.InsertSpell(ctx, writeMonsters.InsertSpellParams{
-		Name:                        NewText(spell.Name),
-		CastLevel:                   NewText(spell.CastLevel),
-		SpellBaseLevel:              NewText(spell.SpellBaseLevel),
-		Description:                 NewText(spell.Description),
-		Range:                       NewText(spell.Range),
-		CastTime:                    NewText(spell.CastTime),
-		CastRequirements:            NewText(spell.CastRequirements),
-		Rarity:                      NewText(spell.Rarity),
-		AtWill:                      pgtype.Bool{Bool: spell.AtWill, Valid: true},
-		SpellCastingBlockLocationID: NewText(spell.SpellCastingBlockLocationID),
-		Uses:                        NewText(spell.Uses),
-		Targets:                     NewText(spell.Targets),
-		Ritual:                      pgtype.Bool{Bool: spell.Ritual, Valid: true},
-	})
+// KickOffSync downloads every registered data source and loads them all into
+// one staging copy of the bestiary. The copy only replaces the live data once
+// it passes the integrity checks; otherwise the live data is left untouched.
+// Each source is recorded as its own run in sync_runs along with the files
+// that failed. Only one sync runs at a time across every instance; otherwise
+// ErrSyncInProgress is returned.
+func KickOffSync(cfg config.Config) error {
+	registry, err := SourcesFromConfig(cfg)
 	if err != nil {
-		return spellId, fmt.Errorf("unable to write spell %w", err)
+		return err
 	}
-	err = queries.InsertSpellArea(ctx, writeMonsters.InsertSpellAreaParams{
-		SpellID:  NewText(spellId),
-		AreaType: NewText(spell.Area.Type),
-		Value:    NewText(spell.Area.Value),
-		Detail:   NewText(spell.Area.Detail),
-	})
+	ctx := context.Background()
+	lock, runs, err := beginSync(ctx, cfg, registry.Sources())
 	if err != nil {
-		return spellId, fmt.Errorf("unable to write spell area %w", err)
+		return err
 	}
-	err = queries.InsertSpellDuration(ctx, writeMonsters.InsertSpellDurationParams{
-		SpellID:   NewText(spellId),
-		Sustained: pgtype.Bool{Bool: spell.Duration.Sustained, Valid: true},
-		Duration:  NewText(spell.Duration.Duration),
-	})
+	defer lock.release()
+	return finishSyncs(ctx, cfg, runs, nil)
+}
+
+// pendingRun is the sync of one source, recorded by beginSync and run by
+// finishSyncs.
+type pendingRun struct {
+	id        int32
+	startedAt time.Time
+	source    Source
+}
+
+// beginSync takes the sync lock and records a run for each of sources, so
+// every run id is known before the first one starts.
+func beginSync(ctx context.Context, cfg config.Config, sources []Source) (*syncLock, []pendingRun, error) {
+	lock, err := acquireSyncLock(ctx, cfg.DBPool)
 	if err != nil {
-		return spellId, fmt.Errorf("unable to write spell duration %w", err)
+		return nil, nil, err
 	}
-	err = queries.InsertSpellDefences(ctx, writeMonsters.InsertSpellDefencesParams{
-		SpellID: NewText(spellId),
-		Save:    NewText(spell.Defense.Save),
-		Basic:   pgtype.Bool{Bool: spell.Defense.Basic, Valid: true},
-	})
+	runs, err := createSyncRuns(ctx, cfg, sources)
 	if err != nil {
-		return spellId, fmt.Errorf("failed to write spell defence block %w", err)
+		lock.release()
+		logger.Log.Error("Failed to record the start of the sync", "err", err)
+		return nil, nil, err
 	}
-
-	return spellId, nil
+	return lock, runs, nil
 }
 
-func ProcessInnateMagic(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	// -- name: InsertInnateSpellCasting :one
-	for i := range len(monster.SpellCasting.InnateSpellCasting) {
-		castingId, err := queries.InsertInnateSpellCasting(ctx, writeMonsters.InsertInnateSpellCastingParams{
-			MonsterID:      NewInt4(int(id)),
-			Dc:             NewInt4(monster.SpellCasting.InnateSpellCasting[i].DC),
-			Tradition:      NewText(monster.SpellCasting.InnateSpellCasting[i].Tradition),
-			Mod:            NewText(monster.SpellCasting.InnateSpellCasting[i].Mod),
-			SpellcastingID: NewText(monster.SpellCasting.InnateSpellCasting[i].ID),
-			Description:    NewText(monster.SpellCasting.InnateSpellCasting[i].Description),
-			Name:           NewText(monster.SpellCasting.InnateSpellCasting[i].Name),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to write innatespellcasting %w", err)
-		}
-		for j := range len(monster.SpellCasting.InnateSpellCasting[i].SpellUses) {
-			//For each spell use theres a spell, write it to spell table AND write to spell use table.
-			spellId, err := processSpellGeneric(ctx, queries, monster, monster.SpellCasting.InnateSpellCasting[i].SpellUses[j].Spell)
-			if err != nil {
-				return fmt.Errorf("unable to process spell to db %w", err)
-			}
-			err = queries.InsertInnateSpellUse(ctx, writeMonsters.InsertInnateSpellUseParams{
-				InnateSpellCastingID: NewInt4(int(castingId)),
-				SpellID:              NewText(spellId),
-				Level:                NewInt4(monster.SpellCasting.InnateSpellCasting[i].SpellUses[j].Level),
-				Uses:                 NewText(monster.SpellCasting.InnateSpellCasting[i].SpellUses[j].Uses),
-			})
-			if err != nil {
-				return fmt.Errorf("unable to write innatespelluse %w", err)
-			}
-		}
+func createSyncRuns(ctx context.Context, cfg config.Config, sources []Source) ([]pendingRun, error) {
+	tx, err := cfg.DBPool.Begin(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
-
-func ProcessFocusMagic(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	// -- name: InsertFocusSpellCasting :one
-	for i := range len(monster.SpellCasting.FocusSpellCasting) {
-		castingId, err := queries.InsertFocusSpellCasting(ctx, writeMonsters.InsertFocusSpellCastingParams{
-			MonsterID:      NewInt4(int(id)),
-			Dc:             NewInt4(monster.SpellCasting.FocusSpellCasting[i].DC),
-			Tradition:      NewText(monster.SpellCasting.FocusSpellCasting[i].Tradition),
-			Mod:            NewText(monster.SpellCasting.FocusSpellCasting[i].Mod),
-			SpellcastingID: NewText(monster.SpellCasting.FocusSpellCasting[i].ID),
-			Description:    NewText(monster.SpellCasting.FocusSpellCasting[i].Description),
-			Name:           NewText(monster.SpellCasting.FocusSpellCasting[i].Name),
-			CastLevel:      NewText(monster.SpellCasting.FocusSpellCasting[i].CastLevel),
+	defer tx.Rollback(ctx)
+	queries := writeMonsters.New(tx)
+	startedAt := time.Now()
+	runs := make([]pendingRun, len(sources))
+	for i, source := range sources {
+		id, err := queries.CreateSyncRun(ctx, writeMonsters.CreateSyncRunParams{
+			StartedAt: NewTimestamptz(startedAt),
+			Source:    source.Name,
 		})
 		if err != nil {
-			return fmt.Errorf("unable to write focus spellcasting %w", err)
-		}
-		for j := range len(monster.SpellCasting.FocusSpellCasting[i].FocusSpellList) {
-			spellId, err := processSpellGeneric(ctx, queries, monster, monster.SpellCasting.FocusSpellCasting[i].FocusSpellList[j])
-			if err != nil {
-				return fmt.Errorf("unable to write focus spell %w", err)
-			}
-			// Write each spell associatation.
-			err = queries.InsertFocusSpellsCasts(ctx, writeMonsters.InsertFocusSpellsCastsParams{
-				FocusSpellCastingID: NewInt4(int(castingId)),
-				SpellID:             NewText(spellId),
-			})
-			if err != nil {
-				return fmt.Errorf("unable to write focus spell casts %w", err)
-			}
+			return nil, err
 		}
+		runs[i] = pendingRun{id: id, startedAt: startedAt, source: source}
 	}
-	return nil
+	return runs, tx.Commit(ctx)
 }
 
-func ProcessPreparedMagic(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	// -- name: InsertPreparedSpellCasting :one
-	for i := range len(monster.SpellCasting.PreparedSpellCasting) {
-		castingId, err := queries.InsertPreparedSpellCasting(ctx, writeMonsters.InsertPreparedSpellCastingParams{
-			MonsterID:      pgtype.Int4{Int32: id},
-			Dc:             NewInt4(monster.SpellCasting.PreparedSpellCasting[i].DC),
-			Tradition:      NewText(monster.SpellCasting.PreparedSpellCasting[i].Tradition),
-			Mod:            NewText(monster.SpellCasting.PreparedSpellCasting[i].Mod),
-			SpellcastingID: NewText(monster.SpellCasting.PreparedSpellCasting[i].ID),
-			Description:    NewText(monster.SpellCasting.PreparedSpellCasting[i].Description),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to write prepared Spellcasting %w", err)
-		}
-		for j := range len(monster.SpellCasting.PreparedSpellCasting[i].Slots) {
-			//For each spell use theres a spell, write it to spell table AND write to spell use table.
-			spellId, err := processSpellGeneric(ctx, queries, monster, monster.SpellCasting.PreparedSpellCasting[i].Slots[j].Spell)
-			if err != nil {
-				return fmt.Errorf("unable to process spell to db %w", err)
-			}
-			err = queries.InsertPreparedSlots(ctx, writeMonsters.InsertPreparedSlotsParams{
-				PreparedSpellCastingID: NewInt4(int(castingId)),
-				SpellID:                NewText(spellId),
-				Level:                  NewText(monster.SpellCasting.PreparedSpellCasting[i].Slots[j].Level),
-			})
-			if err != nil {
-				return fmt.Errorf("unable to insert prepared spell slots %w", err)
-			}
-		}
-	}
-	return nil
+// stagedRun is how the sync of one source went, up to the swap.
+type stagedRun struct {
+	run    pendingRun
+	report LoadReport
+	source SyncSource
+	err    error
+	// loaded is set once the source started writing to the staging schema.
+	// A failure after that leaves it partly loaded.
+	loaded bool
 }
 
-func ProcessSpontaneousMagic(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	// -- name: InsertSpontaneousSpellCasting :one
-	for i := range len(monster.SpellCasting.SpontaneousSpellCasting) {
-		spellCastingId, err := queries.InsertSpontaneousSpells(ctx, writeMonsters.InsertSpontaneousSpellsParams{
-			MonsterID: NewInt4(int(id)),
-			Dc:        NewInt4(monster.SpellCasting.SpontaneousSpellCasting[i].DC),
-			IDString:  NewText(monster.SpellCasting.SpontaneousSpellCasting[i].ID),
-			Tradition: NewText(monster.SpellCasting.SpontaneousSpellCasting[i].Tradition),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to insertSpontaneousSpells %w", err)
-		}
-		for j := range len(monster.SpellCasting.SpontaneousSpellCasting[i].SpellList) {
-			spellID, err := processSpellGeneric(ctx, queries, monster, monster.SpellCasting.SpontaneousSpellCasting[i].SpellList[j])
-			if err != nil {
-				return fmt.Errorf("failed to process generic spell %w", err)
-			}
-			err = queries.InsertSpontaneousSpellList(ctx, writeMonsters.InsertSpontaneousSpellListParams{
-				SpontaneousSpellCastingID: NewInt4(int(spellCastingId)),
-				SpellID:                   NewText(spellID),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to insert spell List stuff %w", err)
-			}
-		}
-		for k := range len(monster.SpellCasting.SpontaneousSpellCasting[i].Slots) {
-			err := queries.InsertSpontaneousSpellSlots(ctx, writeMonsters.InsertSpontaneousSpellSlotsParams{
-				SpontaneousSpellCastingID: NewInt4(int(spellCastingId)),
-				Level:                     NewText(monster.SpellCasting.SpontaneousSpellCasting[i].Slots[k].Level),
-				Casts:                     NewText(monster.SpellCasting.SpontaneousSpellCasting[i].Slots[k].Casts),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to assign spell slots in spontaneous block %w", err)
-			}
-		}
-	}
-	return nil
+// finishSyncs loads every run beginSync recorded into one staging schema and
+// swaps it in once, so the previous generation is always the bestiary from
+// before the sync and RollbackSync undoes all of it. A source that fails
+// before loading anything, or whose upstream is unchanged, keeps its live
+// records without holding back the others. One that fails while loading or
+// fails the integrity checks cannot be separated from the rest of the
+// staging schema, so then nothing is swapped in. A nil imp fetches every
+// source. It returns the errors of the runs that failed.
+func finishSyncs(ctx context.Context, cfg config.Config, runs []pendingRun, imp *ImportOptions) error {
+	staging := &lazyStaging{cfg: cfg}
+	defer staging.close()
+	results := make([]stagedRun, len(runs))
+	for i, run := range runs {
+		results[i] = runSync(ctx, cfg, staging, run, imp)
+	}
+	swapStaged(ctx, cfg, results)
+
+	// The outcome is recorded even when ctx was cancelled by a shutdown.
+	recordCtx := context.WithoutCancel(ctx)
+	var errs []error
+	for _, result := range results {
+		if err := FinishSyncRun(recordCtx, cfg, result.run.id, result.source, result.report, result.err); err != nil {
+			logger.Log.Error("Failed to record the sync run", "run", result.run.id, "err", err)
+		}
+		switch {
+		case errors.Is(result.err, ErrUpstreamUnchanged):
+			logger.Log.Info("Upstream data is unchanged, skipped the sync", "run", result.run.id, "source", result.run.source.Name, "version", result.source.Version)
+		case result.err != nil:
+			errs = append(errs, fmt.Errorf("failed to sync %s %w", result.run.source.Name, result.err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func ProcessMagic(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	if monster.SpellCasting.InnateSpellCasting != nil {
-		err := ProcessInnateMagic(ctx, queries, monster, id)
-		if err != nil {
-			return fmt.Errorf("failed to parse an innate spellcasting Block %w", err)
-		}
+// swapStaged checks the staging schema once every source is loaded and swaps
+// it in. When it cannot be, every run that loaded into it fails.
+func swapStaged(ctx context.Context, cfg config.Config, results []stagedRun) {
+	if !slices.ContainsFunc(results, func(result stagedRun) bool { return result.loaded }) {
+		return
 	}
-	if monster.SpellCasting.FocusSpellCasting != nil {
-		err := ProcessFocusMagic(ctx, queries, monster, id)
-		if err != nil {
-			return fmt.Errorf("failed to parse a focus spellcasting block %w", err)
-		}
+	stats, err := GatherStagingStats(ctx, cfg.DBPool)
+	if err != nil {
+		logger.Log.Error("Failed to check the staging schema", "err", err)
+		failLoaded(results, err)
+		return
 	}
-	if monster.SpellCasting.PreparedSpellCasting != nil {
-		err := ProcessPreparedMagic(ctx, queries, monster, id)
-		if err != nil {
-			return fmt.Errorf("failed to process a prepared spellcasting %w", err)
-		}
+	if !settleStagedRuns(results, stats) {
+		logger.Log.Error("Staged sync rejected, live data left in place")
+		return
 	}
-	if monster.SpellCasting.SpontaneousSpellCasting != nil {
-		err := ProcessSpontaneousMagic(ctx, queries, monster, id)
-		if err != nil {
-			return fmt.Errorf("failed to process a spontaneous spellcasting block %w", err)
-		}
+	if err := SwapStaging(ctx, cfg.DBPool); err != nil {
+		logger.Log.Error("Failed to swap in the staged data", "err", err)
+		failLoaded(results, err)
+		return
 	}
-	return nil
+	logger.Log.Info("Swapped in the new bestiary generation",
+		"monsters", stats.StagedMonsters, "hazards", stats.StagedHazards)
 }
 
-func ProcessItems(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-
-	for i := range len(monster.Inventory) {
-		dbID := uuid.New().String()
-		itemId, err := queries.InsertItems(ctx, writeMonsters.InsertItemsParams{
-			ID:          dbID,
-			MonsterID:   NewInt4(int(id)),
-			Name:        NewText(monster.Inventory[i].Name),
-			Category:    NewText(monster.Inventory[i].Category),
-			Description: NewText(monster.Inventory[i].Description),
-			Level:       NewText(monster.Inventory[i].Level),
-			Rarity:      NewText(monster.Inventory[i].Rarity),
-			Bulk:        NewText(monster.Inventory[i].Bulk),
-			Quantity:    NewText(monster.Inventory[i].Quantity),
-			PricePer:    NewInt4(monster.Inventory[i].Price.Per),
-			PriceCp:     NewInt4(monster.Inventory[i].Price.CP),
-			PriceSp:     NewInt4(monster.Inventory[i].Price.SP),
-			PriceGp:     NewInt4(monster.Inventory[i].Price.GP),
-			PricePp:     NewInt4(monster.Inventory[i].Price.PP),
-		})
-		if err != nil {
-			return fmt.Errorf("failed to write item %s, %w", monster.Inventory[i].Name, err)
-		}
-		// write traits.
-		for j := range len(monster.Inventory[i].Traits) {
-			err := queries.InsertItemTraits(ctx, writeMonsters.InsertItemTraitsParams{
-				ItemID: pgtype.Text{String: itemId},
-				Trait:  pgtype.Text{String: monster.Inventory[i].Traits[j]},
-			})
-			if err != nil {
-				return fmt.Errorf("failed to write item traits %w", err)
+// settleStagedRuns runs the integrity checks for every source loaded into
+// staging and reports whether it can be swapped in. When one source failed,
+// the others that loaded are rejected along with it.
+func settleStagedRuns(results []stagedRun, stats StagingStats) bool {
+	var failed []string
+	for i := range results {
+		result := &results[i]
+		if !result.loaded {
+			continue
+		}
+		if result.err == nil {
+			if problems := CheckIntegrity(stats, result.report); len(problems) > 0 {
+				logger.Log.Error("Staged data failed integrity checks", "source", result.run.source.Name, "problems", problems)
+				result.err = fmt.Errorf("%w: %s", ErrIntegrityCheck, strings.Join(problems, "; "))
 			}
 		}
+		if result.err != nil {
+			failed = append(failed, result.run.source.Name)
+		}
 	}
-	return nil
+	if len(failed) == 0 {
+		return true
+	}
+	failLoaded(results, fmt.Errorf("%w: not swapped in because %s failed", ErrIntegrityCheck, strings.Join(failed, ", ")))
+	return false
 }
 
-func writeTraits(ctx context.Context, queries *writeMonsters.Queries, monster structs.Monster, id int32) error {
-	for i := range len(monster.Traits.TraitList) {
-		err := queries.InsertMonsterTraits(ctx, writeMonsters.InsertMonsterTraitsParams{
-			MonsterID: NewInt4(int(id)),
-			Trait:     NewText(monster.Traits.TraitList[i]),
-		})
-		if err != nil {
-			return fmt.Errorf("unable to write traits to trait table %w", err)
+// failLoaded fails every run that loaded into staging without an error of
+// its own.
+func failLoaded(results []stagedRun, err error) {
+	for i := range results {
+		if results[i].loaded && results[i].err == nil {
+			results[i].err = err
 		}
 	}
-	return nil
 }
 
-func WriteMonsterToDb(monster structs.Monster, cfg config.Config) error {
-	logger.Log.Info(fmt.Sprintf("%+v", monster))
-	ctx := context.Background()
-	// ✅ 2. Begin a transaction
-	tx, err := cfg.DBPool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to start transaction %w", err)
-	}
-
-	//prep main params
-	monsterParams := PrepMonsterParams(monster)
-
-	queries := writeMonsters.New(cfg.DBPool)
+// lazyStaging prepares the staging schema the first time a source needs it,
+// so a sync that finds every source unchanged copies nothing.
+type lazyStaging struct {
+	cfg    config.Config
+	staged config.Config
+	err    error
+	opened bool
+}
 
-	id, err := queries.InsertMonster(ctx, monsterParams)
-	if err != nil {
-		return fmt.Errorf("failed to insert Monster %w", err)
-	}
-	logger.Log.Info(fmt.Sprintf("Succesfully started the transaction for ID %d", id))
-	err = writeTraits(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to write traits %w", err)
+func (s *lazyStaging) open(ctx context.Context) (config.Config, error) {
+	if !s.opened {
+		s.opened = true
+		s.staged, s.err = OpenStaging(ctx, s.cfg)
 	}
-	//for each immunities
-	err = writeImmunites(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to write immunites to db %w", err)
-	}
-	err = ProcessWeakAndResist(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to process weaknesses and resistances: %w", err)
-	}
-	err = ProcessLanguages(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to write languages to db %w", err)
+	return s.staged, s.err
+}
+
+func (s *lazyStaging) close() {
+	if s.opened && s.err == nil {
+		s.staged.DBPool.Close()
 	}
-	err = ProcessSenses(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to write senses to db %w", err)
+}
+
+// lastSyncSource returns what the last successful sync of the data source
+// named name loaded. ok is false when there has not been one.
+func lastSyncSource(ctx context.Context, cfg config.Config, name string) (source SyncSource, ok bool, err error) {
+	last, err := writeMonsters.New(cfg.DBPool).GetLastSucceededSyncRun(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return SyncSource{}, false, nil
 	}
-	err = ProcessSkills(ctx, queries, monster, id)
 	if err != nil {
-		return fmt.Errorf("failed to process skills to db %w", err)
+		return SyncSource{}, false, fmt.Errorf("failed to look up the last sync %w", err)
 	}
-	err = ProcessMovements(ctx, queries, monster, id)
+	return SyncSource{Version: last.SourceVersion.String, Checksum: last.ArchiveChecksum.String}, true, nil
+}
+
+// runSync fetches the data of one source, or opens imp, and loads it into
+// staging.
+func runSync(ctx context.Context, cfg config.Config, staging *lazyStaging, run pendingRun, imp *ImportOptions) stagedRun {
+	last, hasLast, err := lastSyncSource(ctx, cfg, run.source.Name)
 	if err != nil {
-		return fmt.Errorf("failed to process movements into db %w", err)
+		return stagedRun{run: run, err: err}
 	}
-	err = ProcessAction(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to process action to db %w", err)
+	var input syncInput
+	if imp == nil {
+		// The download is only needed for the length of the sync.
+		defer os.Remove(cfg.SYNC_ARCHIVE_PATH)
+		input, err = run.source.fetch(ctx, cfg, last, hasLast)
+	} else {
+		input, err = openImport(*imp, last, hasLast)
 	}
-	err = ProcessReaction(ctx, queries, monster, id)
 	if err != nil {
-		return fmt.Errorf("failed to process reaction to db %w", err)
+		return stagedRun{run: run, source: input.source, err: err}
 	}
-	err = ProcessPassive(ctx, queries, monster, id)
+	return loadSyncInput(ctx, staging, run, input)
+}
+
+// syncInput is the set of documents a sync loads: an archive streamed
+// without extracting it, or files already on disk.
+type syncInput struct {
+	// archive is the .tar.gz to stream. When empty, files are read instead.
+	archive string
+	// root is the top of the directory files were listed from; they are
+	// keyed relative to it.
+	root   string
+	files  []string
+	source SyncSource
+	// complete is false when some files could not be listed.
+	complete bool
+}
+
+// fetchUpstream downloads REPO_URL, or the release SYNC_RELEASE selects. It
+// returns ErrUpstreamUnchanged as soon as it can tell nothing changed since
+// last.
+func fetchUpstream(ctx context.Context, cfg config.Config, last SyncSource, hasLast bool) (syncInput, error) {
+	var input syncInput
+	cfg, release, err := resolveRelease(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to process free action to db %w", err)
+		logger.Log.Error("Sync failed to select the upstream release", "release", cfg.SYNC_RELEASE, "err", err)
+		return input, err
 	}
-	err = ProcessAttacks(ctx, queries, monster, id)
+	input.source.Release = release
+	input.source.Version, err = ResolveUpstreamCommit(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to process attack to db %w", err)
+		logger.Log.Warn("Could not resolve the upstream commit, comparing archive checksums instead", "err", err)
 	}
-	err = ProcessMagic(ctx, queries, monster, id)
-	if err != nil {
-		return fmt.Errorf("failed to process spellcasting blocks %w", err)
+	commitResolved := input.source.Version != ""
+	if hasLast && commitResolved && input.source.unchangedSince(last, true) {
+		return input, ErrUpstreamUnchanged
 	}
 
-	err = ProcessItems(ctx, queries, monster, id)
+	logger.Log.Debug("About to go get the archive")
+	input.source.Checksum, err = GetRepoArchive(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to process items into db, %w", err)
+		// Loading whatever archive is left over would record it under the
+		// version just resolved.
+		logger.Log.Error("Sync failed at archive download", "err", err)
+		return input, err
 	}
-
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to commit transaction close %w", err)
+	if hasLast && !commitResolved && input.source.unchangedSince(last, false) {
+		input.source.Version = last.Version
+		return input, ErrUpstreamUnchanged
 	}
-	return nil
-
+	input.archive = cfg.SYNC_ARCHIVE_PATH
+	input.complete = true
+	return input, nil
 }
 
-func LoadEachJSON(cfg config.Config, path string) error {
-	fmt.Println("Path is :", path)
-	data, err := os.ReadFile(path)
+// listInput lists the pack files of the archive unpacked under dir that are
+// in one of packs, or in any pack when packs is empty.
+func listInput(dir string, source SyncSource, packs []string) syncInput {
+	input := syncInput{root: archiveRoot(dir), source: source, complete: true}
+	fileList, err := GetListofJSON(input.root)
 	if err != nil {
+		logger.Log.Error("Failed to get the list of files to process")
 		logger.Log.Error(err.Error())
-		return err
+		input.complete = false
+	}
+	input.files = filterPacks(fileList, packs)
+	logger.Log.Info(fmt.Sprintf("%v", input.files))
+	if input.source.Version == "" {
+		input.source.Version = filepath.Base(input.root)
 	}
+	return input
+}
 
-	if gjson.Get(string(data), "type").String() == "npc" {
-		fmt.Println("Found a monster")
-		monster := ParseCoreData(string(data))
-		//Parse items and pass it just the items list then attach the return values to monster.
-		itemsList := gjson.Get(string(data), "items")
-		// ItemsList := gjson.Get(string(data), "items").String()
-		// fmt.Println(ItemsList)
-		var spells []structs.Spell
-		monster.FreeActions, monster.Actions, monster.Reactions, monster.Passives, monster.SpellCasting, spells, monster.Melees, monster.Ranged, monster.Inventory, err = ParseItems(itemsList)
-		if err != nil {
-			return err
-		}
-		AssignSpell(&spells, &monster.SpellCasting)
+// loadSyncInput loads input as the data of the source of run into staging.
+// The integrity checks and the swap are left to finishSyncs.
+func loadSyncInput(ctx context.Context, staging *lazyStaging, run pendingRun, input syncInput) stagedRun {
+	src := run.source
+	result := stagedRun{run: run, source: input.source}
+	// Retiring records that were not seen is only safe when every file was
+	// read; a partial run would retire whatever it failed to load.
+	complete := input.complete
 
-		err = WriteMonsterToDb(monster, cfg)
+	docs := fileDocuments(filterPacks(input.files, src.Packs), input.root)
+	var archive *packArchive
+	if input.archive != "" {
+		f, err := os.Open(input.archive)
 		if err != nil {
-			return (err)
+			result.err = fmt.Errorf("failed to open archive %w", err)
+			return result
 		}
-		// err = parseJSON(data)
-		// if err != nil {
-		// 	logger.Log.Error(fmt.Sprintf("Error Parsing file %s", path))
-		// }
-		// // WRite it out to a json
-		// // err = os.WriteFile("example-monster.json", jsonData, 0644)
-		// // if err != nil {
-		// // 	logger.Log.Error("Error writting JSON:", err)
-		// // }
+		defer f.Close()
+		archive = newPackArchive(input.archive, input.source.Packs)
+		archive.include = src.Packs
+		docs = archive.documents(ctx, f)
 	}
 
-	return nil
-}
-
-func KickOffSync(cfg config.Config) error {
-	logger.Log.Debug("About to go get the archive")
-	err := GetRepoArchive(cfg)
+	cfg, err := staging.open(ctx)
 	if err != nil {
-		logger.Log.Error("Sync failed at archive download")
+		logger.Log.Error("Failed to prepare the staging schema", "err", err)
+		result.report = LoadReport{Files: len(input.files)}
+		result.err = err
+		return result
 	}
-	err = extractTarball("repo_archive.tar.gz", "files")
-	if err != nil {
-		logger.Log.Error("Failed to unpack tarball")
-		logger.Log.Error(err.Error())
+	result.loaded = true
+
+	result.report = ingest(ctx, cfg, src, docs, run.startedAt, IngestOptionsFromConfig(cfg), previousHashes(ctx, cfg, src.Name))
+	report := result.report
+	if archive != nil && result.source.Version == "" {
+		result.source.Version = archive.root
 	}
-	fileList, err := GetListofJSON("./files")
-	if err != nil {
-		logger.Log.Error("Failed to get the list of files to process")
-		logger.Log.Error(err.Error())
+	if err := recordSourceFiles(ctx, cfg, src.Name, report.sources, run.startedAt); err != nil {
+		logger.Log.Error("Failed to record the files read", "err", err)
+		result.err = err
+		return result
 	}
-	logger.Log.Info(fmt.Sprintf("%v", fileList))
-	//
-
-	for _, value := range fileList {
-		err = LoadEachJSON(cfg, value)
-		if err != nil {
-			logger.Log.Error(err.Error())
-		}
-
+	if err := stampDataVersion(ctx, cfg, src.Name, result.source, run.startedAt); err != nil {
+		logger.Log.Error("Failed to record the data version", "err", err)
+		result.err = err
+		return result
 	}
-	return nil
-}
-func ManageDBSync(cfg config.Config) error {
-	// Go routine to wait until a certain unix time (3AM PST by default but managed by config) then go get the new tarball every week and sync it to the db
-	loc, err := time.LoadLocation("America/Los_Angeles")
-	if err != nil {
-		return fmt.Errorf("invalid timezone: %w", err)
+	for _, failure := range report.Failures {
+		logger.Log.Error("Failed to load file", "path", failure.Path, "err", failure.Err)
 	}
-
-	// Define cron syntax: Run at 3 AM PST every Tuesday
-	c := cron.New(cron.WithLocation(loc))
-	_, err = c.AddFunc("0 3 * * 2", func() { KickOffSync(cfg) }) // "2" represents Tuesday in cron syntax
-	if err != nil {
-		return err
+	if report.Failed > 0 {
+		complete = false
 	}
-
-	c.Start()
-	fmt.Println("Scheduled job to run every Tuesday at 3 AM PST")
-	{
+	switch {
+	case len(result.source.Packs) > 0:
+		logger.Log.Info("Sync was restricted to some packs, not retiring records missing from this run")
+	case complete:
+		monsters, hazards, err := RetireUnseen(ctx, cfg, src.Name, run.startedAt)
+		if err != nil {
+			logger.Log.Error(err.Error())
+			result.err = err
+			return result
+		}
+		logger.Log.Info(fmt.Sprintf("Retired %d monsters and %d hazards removed from %s", monsters, hazards, src.Name))
+	default:
+		logger.Log.Warn("Sync was incomplete, not retiring records missing from this run")
 	}
-	return nil // Keep the program running
+	logger.Log.Info("Staged the data source", "source", src.Name, "files", report.Files, "failed", report.Failed)
+	return result
 }
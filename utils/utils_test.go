@@ -3031,27 +3031,27 @@ func TestParseSaves(t *testing.T) {
 
 	result := ParseSaves(data)
 	expected := structs.Saves{
-		Fort:       "25",
+		Fort:       25,
 		FortDetail: "",
-		Ref:        "22",
+		Ref:        22,
 		RefDetail:  "",
-		Will:       "27",
+		Will:       27,
 		WillDetail: "",
 	}
 	if result.Fort != expected.Fort {
-		t.Errorf("Expected Fort %s, got %s", expected.Fort, result.Fort)
+		t.Errorf("Expected Fort %d, got %d", expected.Fort, result.Fort)
 	}
 	if result.FortDetail != expected.FortDetail {
 		t.Errorf("Expected FortDetail %s, got %s", expected.FortDetail, result.FortDetail)
 	}
 	if result.Will != expected.Will {
-		t.Errorf("Expected Will %s, got %s", expected.Will, result.Will)
+		t.Errorf("Expected Will %d, got %d", expected.Will, result.Will)
 	}
 	if result.WillDetail != expected.WillDetail {
 		t.Errorf("Expected WillDetail %s, got %s", expected.WillDetail, result.WillDetail)
 	}
 	if result.Ref != expected.Ref {
-		t.Errorf("Expected Ref %s, got %s", expected.Ref, result.Ref)
+		t.Errorf("Expected Ref %d, got %d", expected.Ref, result.Ref)
 	}
 	if result.RefDetail != expected.RefDetail {
 		t.Errorf("Expected RefDetail %s, got %s", expected.RefDetail, result.RefDetail)
@@ -3928,24 +3928,24 @@ func TestParseCoreData(t *testing.T) {
 				"plant",
 			}},
 		Attributes: structs.Attributes{
-			Str: "7",
-			Dex: "3",
-			Con: "4",
-			Wis: "5",
-			Int: "3",
-			Cha: "4",
+			Str: 7,
+			Dex: 3,
+			Con: 4,
+			Wis: 5,
+			Int: 3,
+			Cha: 4,
 		},
-		Level: "14",
+		Level: 14,
 		Saves: structs.Saves{
-			Fort:       "25",
+			Fort:       25,
 			FortDetail: "",
-			Ref:        "22",
+			Ref:        22,
 			RefDetail:  "",
-			Will:       "27",
+			Will:       27,
 			WillDetail: "",
 		},
 		AClass: structs.AC{
-			Value:  "36",
+			Value:  36,
 			Detail: "",
 		},
 		HP: structs.HP{
@@ -4052,46 +4052,46 @@ func TestParseCoreData(t *testing.T) {
 		}
 	}
 	if result.Attributes.Str != expected.Attributes.Str {
-		t.Errorf("Expected Str %s, got %s", expected.Attributes.Str, result.Attributes.Str)
+		t.Errorf("Expected Str %d, got %d", expected.Attributes.Str, result.Attributes.Str)
 	}
 	if result.Attributes.Dex != expected.Attributes.Dex {
-		t.Errorf("Expected Dex %s, got %s", expected.Attributes.Dex, result.Attributes.Dex)
+		t.Errorf("Expected Dex %d, got %d", expected.Attributes.Dex, result.Attributes.Dex)
 	}
 	if result.Attributes.Con != expected.Attributes.Con {
-		t.Errorf("Expected Con %s, got %s", expected.Attributes.Con, result.Attributes.Con)
+		t.Errorf("Expected Con %d, got %d", expected.Attributes.Con, result.Attributes.Con)
 	}
 	if result.Attributes.Wis != expected.Attributes.Wis {
-		t.Errorf("Expected Wis %s, got %s", expected.Attributes.Wis, result.Attributes.Wis)
+		t.Errorf("Expected Wis %d, got %d", expected.Attributes.Wis, result.Attributes.Wis)
 	}
 	if result.Attributes.Int != expected.Attributes.Int {
-		t.Errorf("Expected Int %s, got %s", expected.Attributes.Int, result.Attributes.Int)
+		t.Errorf("Expected Int %d, got %d", expected.Attributes.Int, result.Attributes.Int)
 	}
 	if result.Attributes.Cha != expected.Attributes.Cha {
-		t.Errorf("Expected Cha %s, got %s", expected.Attributes.Cha, result.Attributes.Cha)
+		t.Errorf("Expected Cha %d, got %d", expected.Attributes.Cha, result.Attributes.Cha)
 	}
 	if result.Level != expected.Level {
-		t.Errorf("Expected Level %s, got %s", expected.Level, result.Level)
+		t.Errorf("Expected Level %d, got %d", expected.Level, result.Level)
 	}
 	if result.Saves.Fort != expected.Saves.Fort {
-		t.Errorf("Expected Fort %s, got %s", expected.Saves.Fort, result.Saves.Fort)
+		t.Errorf("Expected Fort %d, got %d", expected.Saves.Fort, result.Saves.Fort)
 	}
 	if result.Saves.FortDetail != expected.Saves.FortDetail {
 		t.Errorf("Expected FortDetail %s, got %s", expected.Saves.FortDetail, result.Saves.FortDetail)
 	}
 	if result.Saves.Ref != expected.Saves.Ref {
-		t.Errorf("Expected Ref %s, got %s", expected.Saves.Ref, result.Saves.Ref)
+		t.Errorf("Expected Ref %d, got %d", expected.Saves.Ref, result.Saves.Ref)
 	}
 	if result.Saves.RefDetail != expected.Saves.RefDetail {
 		t.Errorf("Expected RefDetail %s, got %s", expected.Saves.RefDetail, result.Saves.RefDetail)
 	}
 	if result.Saves.Will != expected.Saves.Will {
-		t.Errorf("Expected Will %s, got %s", expected.Saves.Will, result.Saves.Will)
+		t.Errorf("Expected Will %d, got %d", expected.Saves.Will, result.Saves.Will)
 	}
 	if result.Saves.WillDetail != expected.Saves.WillDetail {
 		t.Errorf("Expected WillDetail %s, got %s", expected.Saves.WillDetail, result.Saves.WillDetail)
 	}
 	if result.AClass.Value != expected.AClass.Value {
-		t.Errorf("Expected AC Value %s, got %s", expected.AClass.Value, result.AClass.Value)
+		t.Errorf("Expected AC Value %d, got %d", expected.AClass.Value, result.AClass.Value)
 	}
 	if result.AClass.Detail != expected.AClass.Detail {
 		t.Errorf("Expected AC Detail %s, got %s", expected.AClass.Detail, result.AClass.Detail)
@@ -4243,7 +4243,7 @@ func TestStringCleaner(t *testing.T) {
 // 			Int: "3",
 // 			Cha: "4",
 // 		},
-// 		Level: "14",
+// 		Level: 14,
 // 		Saves: structs.Saves{
 // 			Fort:       "25",
 // 			FortDetail: "",
@@ -4528,46 +4528,46 @@ func TestStringCleaner(t *testing.T) {
 // // 		}
 // // 	}
 // // 	if result.Attributes.Str != expected.Attributes.Str {
-// // 		t.Errorf("Expected Str %s, got %s", expected.Attributes.Str, result.Attributes.Str)
+// // 		t.Errorf("Expected Str %d, got %d", expected.Attributes.Str, result.Attributes.Str)
 // // 	}
 // // 	if result.Attributes.Dex != expected.Attributes.Dex {
-// // 		t.Errorf("Expected Dex %s, got %s", expected.Attributes.Dex, result.Attributes.Dex)
+// // 		t.Errorf("Expected Dex %d, got %d", expected.Attributes.Dex, result.Attributes.Dex)
 // // 	}
 // // 	if result.Attributes.Con != expected.Attributes.Con {
-// // 		t.Errorf("Expected Con %s, got %s", expected.Attributes.Con, result.Attributes.Con)
+// // 		t.Errorf("Expected Con %d, got %d", expected.Attributes.Con, result.Attributes.Con)
 // // 	}
 // // 	if result.Attributes.Wis != expected.Attributes.Wis {
-// // 		t.Errorf("Expected Wis %s, got %s", expected.Attributes.Wis, result.Attributes.Wis)
+// // 		t.Errorf("Expected Wis %d, got %d", expected.Attributes.Wis, result.Attributes.Wis)
 // // 	}
 // // 	if result.Attributes.Int != expected.Attributes.Int {
-// // 		t.Errorf("Expected Int %s, got %s", expected.Attributes.Int, result.Attributes.Int)
+// // 		t.Errorf("Expected Int %d, got %d", expected.Attributes.Int, result.Attributes.Int)
 // // 	}
 // // 	if result.Attributes.Cha != expected.Attributes.Cha {
-// // 		t.Errorf("Expected Cha %s, got %s", expected.Attributes.Cha, result.Attributes.Cha)
+// // 		t.Errorf("Expected Cha %d, got %d", expected.Attributes.Cha, result.Attributes.Cha)
 // // 	}
 // // 	if result.Level != expected.Level {
-// // 		t.Errorf("Expected Level %s, got %s", expected.Level, result.Level)
+// // 		t.Errorf("Expected Level %d, got %d", expected.Level, result.Level)
 // // 	}
 // // 	if result.Saves.Fort != expected.Saves.Fort {
-// // 		t.Errorf("Expected Fort %s, got %s", expected.Saves.Fort, result.Saves.Fort)
+// // 		t.Errorf("Expected Fort %d, got %d", expected.Saves.Fort, result.Saves.Fort)
 // // 	}
 // // 	if result.Saves.FortDetail != expected.Saves.FortDetail {
 // // 		t.Errorf("Expected FortDetail %s, got %s", expected.Saves.FortDetail, result.Saves.FortDetail)
 // // 	}
 // // 	if result.Saves.Ref != expected.Saves.Ref {
-// // 		t.Errorf("Expected Ref %s, got %s", expected.Saves.Ref, result.Saves.Ref)
+// // 		t.Errorf("Expected Ref %d, got %d", expected.Saves.Ref, result.Saves.Ref)
 // // 	}
 // // 	if result.Saves.RefDetail != expected.Saves.RefDetail {
 // // 		t.Errorf("Expected RefDetail %s, got %s", expected.Saves.RefDetail, result.Saves.RefDetail)
 // // 	}
 // // 	if result.Saves.Will != expected.Saves.Will {
-// // 		t.Errorf("Expected Will %s, got %s", expected.Saves.Will, result.Saves.Will)
+// // 		t.Errorf("Expected Will %d, got %d", expected.Saves.Will, result.Saves.Will)
 // // 	}
 // // 	if result.Saves.WillDetail != expected.Saves.WillDetail {
 // // 		t.Errorf("Expected WillDetail %s, got %s", expected.Saves.WillDetail, result.Saves.WillDetail)
 // // 	}
 // // 	if result.AClass.Value != expected.AClass.Value {
-// // 		t.Errorf("Expected AC Value %s, got %s", expected.AClass.Value, result.AClass.Value)
+// // 		t.Errorf("Expected AC Value %d, got %d", expected.AClass.Value, result.AClass.Value)
 // // 	}
 // // 	if result.AClass.Detail != expected.AClass.Detail {
 // // 		t.Errorf("Expected AC Detail %s, got %s", expected.AClass.Detail, result.AClass.Detail)
@@ -4675,3 +4675,29 @@ func TestStringCleaner(t *testing.T) {
 
 // // }
 // }
+
+func TestParseCoreDataNumericFields(t *testing.T) {
+	data := `{
+        "name": "Rat Swarm",
+        "system": {
+            "details": {"level": {"value": -1}},
+            "attributes": {"ac": {"value": 14}},
+            "abilities": {"str": {"mod": -2}, "dex": {"mod": 3}},
+            "saves": {"fortitude": {"value": 5}, "reflex": {"value": 7}, "will": {"value": 2}}
+        },
+        "type": "npc"
+    }`
+	result := ParseCoreData(data)
+	if result.Level != -1 {
+		t.Errorf("Expected Level -1, got %d", result.Level)
+	}
+	if result.AClass.Value != 14 {
+		t.Errorf("Expected AC Value 14, got %d", result.AClass.Value)
+	}
+	if result.Attributes.Str != -2 || result.Attributes.Dex != 3 {
+		t.Errorf("Expected Str -2 and Dex 3, got %d and %d", result.Attributes.Str, result.Attributes.Dex)
+	}
+	if result.Saves.Fort != 5 || result.Saves.Ref != 7 || result.Saves.Will != 2 {
+		t.Errorf("Expected saves 5/7/2, got %d/%d/%d", result.Saves.Fort, result.Saves.Ref, result.Saves.Will)
+	}
+}
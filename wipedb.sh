@@ -17,8 +17,10 @@ echo "Waiting for PostgreSQL to be ready..."
 # A simple sleep can work, but you might want to use a more robust health-check.
 sleep 10
 
-# 4. Load the schema into the new, empty database.
+# 4. Load the schema into the new, empty database. The bestiary tables live in
+# their own schema so a sync can stage a new copy and swap it in.
 echo "Loading schema from ./schema/schema.sql ..."
-cat ./schema/schema.sql | docker exec -i encounter-builder-postgres psql -U user -d encounterBuilder
+{ echo "CREATE SCHEMA bestiary; SET search_path TO bestiary;"; cat ./schema/schema.sql; } | docker exec -i encounter-builder-postgres psql -U user -d encounterBuilder
+cat ./schema/ops.sql | docker exec -i encounter-builder-postgres psql -U user -d encounterBuilder
 
 echo "Database has been reinitialized with the new schema."
\ No newline at end of file
@@ -11,6 +11,56 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteMonsterChildren = `-- name: DeleteMonsterChildren :exec
+WITH d_monster_traits AS (DELETE FROM monster_traits WHERE monster_id = $1),
+     d_monster_immunities AS (DELETE FROM monster_immunities WHERE monster_id = $1),
+     d_monster_damage_modifiers AS (DELETE FROM monster_damage_modifiers WHERE monster_id = $1),
+     d_monster_languages AS (DELETE FROM monster_languages WHERE monster_id = $1),
+     d_monster_senses AS (DELETE FROM monster_senses WHERE monster_id = $1),
+     d_monster_skills AS (DELETE FROM monster_skills WHERE monster_id = $1),
+     d_monster_movements AS (DELETE FROM monster_movements WHERE monster_id = $1),
+     d_monster_actions AS (DELETE FROM monster_actions WHERE monster_id = $1),
+     d_monster_attacks AS (DELETE FROM monster_attacks WHERE monster_id = $1),
+     d_focus_spell_casting AS (DELETE FROM focus_spell_casting WHERE monster_id = $1),
+     d_innate_spell_casting AS (DELETE FROM innate_spell_casting WHERE monster_id = $1),
+     d_prepared_spell_casting AS (DELETE FROM prepared_spell_casting WHERE monster_id = $1),
+     d_spontaneous_spell_casting AS (DELETE FROM spontaneous_spell_casting WHERE monster_id = $1),
+     d_spells AS (DELETE FROM spells WHERE monster_id = $1)
+DELETE FROM items WHERE monster_id = $1
+`
+
+// Clears every row hanging off a monster before it is rewritten. Grandchild
+// rows go with their parents through ON DELETE CASCADE.
+func (q *Queries) DeleteMonsterChildren(ctx context.Context, monsterID pgtype.Int4) error {
+	_, err := q.db.Exec(ctx, deleteMonsterChildren, monsterID)
+	return err
+}
+
+const getMonsterSyncState = `-- name: GetMonsterSyncState :one
+SELECT id, content_hash, retired_at
+FROM monsters
+WHERE foundry_id = $1 AND pack_path = $2 AND source = $3
+`
+
+type GetMonsterSyncStateParams struct {
+	FoundryID pgtype.Text
+	PackPath  pgtype.Text
+	Source    string
+}
+
+type GetMonsterSyncStateRow struct {
+	ID          int32
+	ContentHash pgtype.Text
+	RetiredAt   pgtype.Timestamptz
+}
+
+func (q *Queries) GetMonsterSyncState(ctx context.Context, arg GetMonsterSyncStateParams) (GetMonsterSyncStateRow, error) {
+	row := q.db.QueryRow(ctx, getMonsterSyncState, arg.FoundryID, arg.PackPath, arg.Source)
+	var i GetMonsterSyncStateRow
+	err := row.Scan(&i.ID, &i.ContentHash, &i.RetiredAt)
+	return i, err
+}
+
 const insertFocusSpellCasting = `-- name: InsertFocusSpellCasting :one
 INSERT INTO focus_spell_casting (monster_id, dc, mod, tradition, spellcasting_id, name, description, cast_level)
 Values($1, $2, $3, $4, $5, $6, $7, $8)
@@ -172,94 +222,6 @@ func (q *Queries) InsertItems(ctx context.Context, arg InsertItemsParams) (strin
 	return id, err
 }
 
-const insertMonster = `-- name: InsertMonster :one
-INSERT INTO monsters (name, 
-                        level, 
-                        focus_points, 
-                        traits_rarity, 
-                        traits_size, 
-                        attr_str, 
-                        attr_dex, 
-                        attr_con, 
-                        attr_wis,
-                        attr_int, 
-                        attr_cha, 
-                        saves_fort, 
-                        saves_fort_detail,
-                        saves_ref, 
-                        saves_ref_detail, 
-                        saves_will, 
-                        saves_will_detail,
-                        saves_exception, 
-                        ac_value, 
-                        ac_detail,
-                        hp_value, 
-                        hp_detail, 
-                        perception_mod, 
-                        perception_detail)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
-RETURNING id
-`
-
-type InsertMonsterParams struct {
-	Name             string
-	Level            pgtype.Text
-	FocusPoints      pgtype.Int4
-	TraitsRarity     pgtype.Text
-	TraitsSize       pgtype.Text
-	AttrStr          pgtype.Text
-	AttrDex          pgtype.Text
-	AttrCon          pgtype.Text
-	AttrWis          pgtype.Text
-	AttrInt          pgtype.Text
-	AttrCha          pgtype.Text
-	SavesFort        pgtype.Text
-	SavesFortDetail  pgtype.Text
-	SavesRef         pgtype.Text
-	SavesRefDetail   pgtype.Text
-	SavesWill        pgtype.Text
-	SavesWillDetail  pgtype.Text
-	SavesException   pgtype.Text
-	AcValue          pgtype.Text
-	AcDetail         pgtype.Text
-	HpValue          pgtype.Int4
-	HpDetail         pgtype.Text
-	PerceptionMod    pgtype.Text
-	PerceptionDetail pgtype.Text
-}
-
-func (q *Queries) InsertMonster(ctx context.Context, arg InsertMonsterParams) (int32, error) {
-	row := q.db.QueryRow(ctx, insertMonster,
-		arg.Name,
-		arg.Level,
-		arg.FocusPoints,
-		arg.TraitsRarity,
-		arg.TraitsSize,
-		arg.AttrStr,
-		arg.AttrDex,
-		arg.AttrCon,
-		arg.AttrWis,
-		arg.AttrInt,
-		arg.AttrCha,
-		arg.SavesFort,
-		arg.SavesFortDetail,
-		arg.SavesRef,
-		arg.SavesRefDetail,
-		arg.SavesWill,
-		arg.SavesWillDetail,
-		arg.SavesException,
-		arg.AcValue,
-		arg.AcDetail,
-		arg.HpValue,
-		arg.HpDetail,
-		arg.PerceptionMod,
-		arg.PerceptionDetail,
-	)
-	var id int32
-	err := row.Scan(&id)
-	return id, err
-}
-
 const insertMonsterAction = `-- name: InsertMonsterAction :one
 INSERT INTO monster_actions (monster_id, action_type, name, text, actions, category, rarity, dc)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
@@ -610,12 +572,15 @@ func (q *Queries) InsertRitualData(ctx context.Context, arg InsertRitualDataPara
 }
 
 const insertSpell = `-- name: InsertSpell :one
-INSERT INTO spells (name, cast_level, spell_base_level, description, range, cast_time, cast_requirements, rarity, at_will, spell_casting_block_location_id, uses, ritual, targets)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+INSERT INTO spells (id, monster_id, foundry_id, name, cast_level, spell_base_level, description, range, cast_time, cast_requirements, rarity, at_will, spell_casting_block_location_id, uses, ritual, targets)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 RETURNING id
 `
 
 type InsertSpellParams struct {
+	ID                          string
+	MonsterID                   pgtype.Int4
+	FoundryID                   pgtype.Text
 	Name                        pgtype.Text
 	CastLevel                   pgtype.Text
 	SpellBaseLevel              pgtype.Text
@@ -633,6 +598,9 @@ type InsertSpellParams struct {
 
 func (q *Queries) InsertSpell(ctx context.Context, arg InsertSpellParams) (string, error) {
 	row := q.db.QueryRow(ctx, insertSpell,
+		arg.ID,
+		arg.MonsterID,
+		arg.FoundryID,
 		arg.Name,
 		arg.CastLevel,
 		arg.SpellBaseLevel,
@@ -778,3 +746,173 @@ func (q *Queries) InsertSpontaneousSpells(ctx context.Context, arg InsertSpontan
 	err := row.Scan(&id)
 	return id, err
 }
+
+const markMonsterSeen = `-- name: MarkMonsterSeen :exec
+UPDATE monsters SET last_seen_at = $2 WHERE id = $1
+`
+
+type MarkMonsterSeenParams struct {
+	ID         int32
+	LastSeenAt pgtype.Timestamptz
+}
+
+func (q *Queries) MarkMonsterSeen(ctx context.Context, arg MarkMonsterSeenParams) error {
+	_, err := q.db.Exec(ctx, markMonsterSeen, arg.ID, arg.LastSeenAt)
+	return err
+}
+
+const retireUnseenMonsters = `-- name: RetireUnseenMonsters :execrows
+UPDATE monsters
+SET retired_at = now()
+WHERE retired_at IS NULL
+  AND (last_seen_at IS NULL OR last_seen_at < $1)
+  AND source = $2
+`
+
+type RetireUnseenMonstersParams struct {
+	LastSeenAt pgtype.Timestamptz
+	Source     string
+}
+
+// Monsters that were not seen by a sync started at $1 have been removed
+// upstream. They stay in the table so saved encounters still resolve.
+func (q *Queries) RetireUnseenMonsters(ctx context.Context, arg RetireUnseenMonstersParams) (int64, error) {
+	result, err := q.db.Exec(ctx, retireUnseenMonsters, arg.LastSeenAt, arg.Source)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertMonster = `-- name: UpsertMonster :one
+INSERT INTO monsters (foundry_id,
+                      pack_path,
+                      content_hash,
+                      last_seen_at,
+                      name,
+                      level,
+                      focus_points,
+                      traits_rarity,
+                      traits_size,
+                      attr_str,
+                      attr_dex,
+                      attr_con,
+                      attr_wis,
+                      attr_int,
+                      attr_cha,
+                      saves_fort,
+                      saves_fort_detail,
+                      saves_ref,
+                      saves_ref_detail,
+                      saves_will,
+                      saves_will_detail,
+                      saves_exception,
+                      ac_value,
+                      ac_detail,
+                      hp_value,
+                      hp_detail,
+                      perception_mod,
+                      perception_detail,
+                      source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)
+ON CONFLICT (source, foundry_id, pack_path) DO UPDATE
+SET content_hash = EXCLUDED.content_hash,
+    last_seen_at = EXCLUDED.last_seen_at,
+    name = EXCLUDED.name,
+    level = EXCLUDED.level,
+    focus_points = EXCLUDED.focus_points,
+    traits_rarity = EXCLUDED.traits_rarity,
+    traits_size = EXCLUDED.traits_size,
+    attr_str = EXCLUDED.attr_str,
+    attr_dex = EXCLUDED.attr_dex,
+    attr_con = EXCLUDED.attr_con,
+    attr_wis = EXCLUDED.attr_wis,
+    attr_int = EXCLUDED.attr_int,
+    attr_cha = EXCLUDED.attr_cha,
+    saves_fort = EXCLUDED.saves_fort,
+    saves_fort_detail = EXCLUDED.saves_fort_detail,
+    saves_ref = EXCLUDED.saves_ref,
+    saves_ref_detail = EXCLUDED.saves_ref_detail,
+    saves_will = EXCLUDED.saves_will,
+    saves_will_detail = EXCLUDED.saves_will_detail,
+    saves_exception = EXCLUDED.saves_exception,
+    ac_value = EXCLUDED.ac_value,
+    ac_detail = EXCLUDED.ac_detail,
+    hp_value = EXCLUDED.hp_value,
+    hp_detail = EXCLUDED.hp_detail,
+    perception_mod = EXCLUDED.perception_mod,
+    perception_detail = EXCLUDED.perception_detail,
+    retired_at = NULL
+RETURNING id
+`
+
+type UpsertMonsterParams struct {
+	FoundryID        pgtype.Text
+	PackPath         pgtype.Text
+	ContentHash      pgtype.Text
+	LastSeenAt       pgtype.Timestamptz
+	Name             string
+	Level            pgtype.Int4
+	FocusPoints      pgtype.Int4
+	TraitsRarity     pgtype.Text
+	TraitsSize       pgtype.Text
+	AttrStr          pgtype.Int4
+	AttrDex          pgtype.Int4
+	AttrCon          pgtype.Int4
+	AttrWis          pgtype.Int4
+	AttrInt          pgtype.Int4
+	AttrCha          pgtype.Int4
+	SavesFort        pgtype.Int4
+	SavesFortDetail  pgtype.Text
+	SavesRef         pgtype.Int4
+	SavesRefDetail   pgtype.Text
+	SavesWill        pgtype.Int4
+	SavesWillDetail  pgtype.Text
+	SavesException   pgtype.Text
+	AcValue          pgtype.Int4
+	AcDetail         pgtype.Text
+	HpValue          pgtype.Int4
+	HpDetail         pgtype.Text
+	PerceptionMod    pgtype.Text
+	PerceptionDetail pgtype.Text
+	Source           string
+}
+
+// Monsters are keyed by their Foundry _id within a pack of a data source so a
+// re-sync updates the existing row instead of adding a copy.
+func (q *Queries) UpsertMonster(ctx context.Context, arg UpsertMonsterParams) (int32, error) {
+	row := q.db.QueryRow(ctx, upsertMonster,
+		arg.FoundryID,
+		arg.PackPath,
+		arg.ContentHash,
+		arg.LastSeenAt,
+		arg.Name,
+		arg.Level,
+		arg.FocusPoints,
+		arg.TraitsRarity,
+		arg.TraitsSize,
+		arg.AttrStr,
+		arg.AttrDex,
+		arg.AttrCon,
+		arg.AttrWis,
+		arg.AttrInt,
+		arg.AttrCha,
+		arg.SavesFort,
+		arg.SavesFortDetail,
+		arg.SavesRef,
+		arg.SavesRefDetail,
+		arg.SavesWill,
+		arg.SavesWillDetail,
+		arg.SavesException,
+		arg.AcValue,
+		arg.AcDetail,
+		arg.HpValue,
+		arg.HpDetail,
+		arg.PerceptionMod,
+		arg.PerceptionDetail,
+		arg.Source,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
@@ -15,6 +15,13 @@ type AttackDamageBlock struct {
 	DamageType pgtype.Text
 }
 
+type DataVersion struct {
+	Release       pgtype.Text
+	SourceVersion string
+	LoadedAt      pgtype.Timestamptz
+	Source        string
+}
+
 type FocusSpellCasting struct {
 	ID             int32
 	MonsterID      pgtype.Int4
@@ -33,6 +40,71 @@ type FocusSpellCastingSpell struct {
 	SpellID             pgtype.Text
 }
 
+type Hazard struct {
+	ID            int32
+	Name          string
+	Level         pgtype.Int4
+	IsComplex     bool
+	TraitsRarity  pgtype.Text
+	StealthValue  pgtype.Int4
+	StealthDetail pgtype.Text
+	Disable       pgtype.Text
+	AcValue       pgtype.Int4
+	Hardness      pgtype.Int4
+	HpValue       pgtype.Int4
+	HpDetail      pgtype.Text
+	SavesFort     pgtype.Int4
+	SavesRef      pgtype.Int4
+	SavesWill     pgtype.Int4
+	Description   pgtype.Text
+	Routine       pgtype.Text
+	Reset         pgtype.Text
+	FoundryID     pgtype.Text
+	PackPath      pgtype.Text
+	ContentHash   pgtype.Text
+	LastSeenAt    pgtype.Timestamptz
+	RetiredAt     pgtype.Timestamptz
+	DataVersion   pgtype.Text
+	Source        string
+}
+
+type HazardAction struct {
+	ID         int32
+	HazardID   pgtype.Int4
+	ActionType pgtype.Text
+	Name       pgtype.Text
+	Text       pgtype.Text
+	Actions    pgtype.Text
+}
+
+type HazardAttack struct {
+	ID             int32
+	HazardID       pgtype.Int4
+	AttackCategory pgtype.Text
+	Name           pgtype.Text
+	AttackType     pgtype.Text
+	ToHitBonus     pgtype.Text
+}
+
+type HazardAttackDamageBlock struct {
+	ID         int32
+	AttackID   pgtype.Int4
+	DamageRoll pgtype.Text
+	DamageType pgtype.Text
+}
+
+type HazardImmunity struct {
+	ID       int32
+	HazardID pgtype.Int4
+	Immunity pgtype.Text
+}
+
+type HazardTrait struct {
+	ID       int32
+	HazardID pgtype.Int4
+	Trait    pgtype.Text
+}
+
 type InnateSpellCasting struct {
 	ID             int32
 	MonsterID      pgtype.Int4
@@ -82,29 +154,36 @@ type ItemTrait struct {
 type Monster struct {
 	ID               int32
 	Name             string
-	Level            pgtype.Text
+	Level            pgtype.Int4
 	FocusPoints      pgtype.Int4
 	TraitsRarity     pgtype.Text
 	TraitsSize       pgtype.Text
-	AttrStr          pgtype.Text
-	AttrDex          pgtype.Text
-	AttrCon          pgtype.Text
-	AttrWis          pgtype.Text
-	AttrInt          pgtype.Text
-	AttrCha          pgtype.Text
-	SavesFort        pgtype.Text
+	AttrStr          pgtype.Int4
+	AttrDex          pgtype.Int4
+	AttrCon          pgtype.Int4
+	AttrWis          pgtype.Int4
+	AttrInt          pgtype.Int4
+	AttrCha          pgtype.Int4
+	SavesFort        pgtype.Int4
 	SavesFortDetail  pgtype.Text
-	SavesRef         pgtype.Text
+	SavesRef         pgtype.Int4
 	SavesRefDetail   pgtype.Text
-	SavesWill        pgtype.Text
+	SavesWill        pgtype.Int4
 	SavesWillDetail  pgtype.Text
 	SavesException   pgtype.Text
-	AcValue          pgtype.Text
+	AcValue          pgtype.Int4
 	AcDetail         pgtype.Text
 	HpDetail         pgtype.Text
 	HpValue          pgtype.Int4
 	PerceptionMod    pgtype.Text
 	PerceptionDetail pgtype.Text
+	FoundryID        pgtype.Text
+	PackPath         pgtype.Text
+	ContentHash      pgtype.Text
+	LastSeenAt       pgtype.Timestamptz
+	RetiredAt        pgtype.Timestamptz
+	DataVersion      pgtype.Text
+	Source           string
 }
 
 type MonsterAction struct {
@@ -223,6 +302,15 @@ type PreparedSpellCasting struct {
 	Description    pgtype.Text
 }
 
+type QuarantinedItem struct {
+	ID       int32
+	RunID    int32
+	Path     string
+	ItemName pgtype.Text
+	Reason   string
+	Raw      string
+}
+
 type RitualDatum struct {
 	ID               int32
 	SpellID          pgtype.Text
@@ -231,8 +319,19 @@ type RitualDatum struct {
 	SecondaryCheck   pgtype.Text
 }
 
+type SourceFile struct {
+	Path        string
+	FoundryID   string
+	PackPath    string
+	ContentHash string
+	LastSeenAt  pgtype.Timestamptz
+	Source      string
+}
+
 type Spell struct {
 	ID                          string
+	MonsterID                   pgtype.Int4
+	FoundryID                   pgtype.Text
 	Name                        pgtype.Text
 	CastLevel                   pgtype.Text
 	SpellBaseLevel              pgtype.Text
@@ -297,3 +396,29 @@ type SpontaneousSpellList struct {
 	SpontaneousSpellCastingID pgtype.Int4
 	SpellID                   pgtype.Text
 }
+
+type SyncFailure struct {
+	ID    int32
+	RunID int32
+	Path  string
+	Error string
+}
+
+type SyncRun struct {
+	ID              int32
+	StartedAt       pgtype.Timestamptz
+	FinishedAt      pgtype.Timestamptz
+	Status          string
+	SourceVersion   pgtype.Text
+	Release         pgtype.Text
+	ArchiveChecksum pgtype.Text
+	PackFilter      []string
+	FilesSeen       int32
+	FilesParsed     int32
+	RecordsInserted int32
+	RecordsUpdated  int32
+	FilesSkipped    int32
+	FilesFailed     int32
+	Error           pgtype.Text
+	Source          string
+}
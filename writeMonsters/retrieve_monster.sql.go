@@ -14,7 +14,13 @@ import (
 const getFullMonsterByID = `-- name: GetFullMonsterByID :one
 SELECT row_to_json(monster_data)
 FROM (
-  SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail,
+  SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail, m.foundry_id, m.pack_path, m.content_hash, m.last_seen_at, m.retired_at, m.data_version, m.source,
+    (
+      SELECT json_agg(mt.trait)
+      FROM monster_traits mt
+      WHERE mt.monster_id = m.id
+    ) AS traits,
+
     (
       SELECT json_agg(mi)
       FROM monster_immunities mi
@@ -258,7 +264,7 @@ FROM (
     ) AS items
 
   FROM monsters m
-  WHERE m.id = $1
+  WHERE m.id = $1 AND m.retired_at IS NULL
 ) monster_data
 `
 
@@ -269,10 +275,42 @@ func (q *Queries) GetFullMonsterByID(ctx context.Context, id int32) ([]byte, err
 	return row_to_json, err
 }
 
+const getMonsterLevelsByIDs = `-- name: GetMonsterLevelsByIDs :many
+SELECT m.id, m.name, m.level
+FROM monsters m
+WHERE m.id = ANY($1::integer[]) AND m.retired_at IS NULL
+`
+
+type GetMonsterLevelsByIDsRow struct {
+	ID    int32
+	Name  string
+	Level pgtype.Int4
+}
+
+func (q *Queries) GetMonsterLevelsByIDs(ctx context.Context, ids []int32) ([]GetMonsterLevelsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, getMonsterLevelsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMonsterLevelsByIDsRow
+	for rows.Next() {
+		var i GetMonsterLevelsByIDsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Level); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getMonstersByLevelRange = `-- name: GetMonstersByLevelRange :many
 SELECT row_to_json(monster_data)
 FROM (
-  SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail,
+  SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail, m.foundry_id, m.pack_path, m.content_hash, m.last_seen_at, m.retired_at, m.data_version, m.source,
     (
       SELECT json_agg(mi)
       FROM monster_immunities mi
@@ -515,13 +553,13 @@ FROM (
       WHERE i.monster_id = m.id
     ) AS items
   FROM monsters m
-  WHERE m.level BETWEEN $1 AND $2
+  WHERE m.level BETWEEN $1 AND $2 AND m.retired_at IS NULL
 ) monster_data
 `
 
 type GetMonstersByLevelRangeParams struct {
-	Level   pgtype.Text
-	Level_2 pgtype.Text
+	Level   pgtype.Int4
+	Level_2 pgtype.Int4
 }
 
 func (q *Queries) GetMonstersByLevelRange(ctx context.Context, arg GetMonstersByLevelRangeParams) ([][]byte, error) {
@@ -545,10 +583,10 @@ func (q *Queries) GetMonstersByLevelRange(ctx context.Context, arg GetMonstersBy
 }
 
 const getMonstersByTrait = `-- name: GetMonstersByTrait :many
-SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail
+SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail, m.foundry_id, m.pack_path, m.content_hash, m.last_seen_at, m.retired_at, m.data_version, m.source
 FROM monsters m
 JOIN monster_traits mt ON m.id = mt.monster_id
-WHERE mt.trait = $1
+WHERE mt.trait = $1 AND m.retired_at IS NULL
 `
 
 func (q *Queries) GetMonstersByTrait(ctx context.Context, trait pgtype.Text) ([]Monster, error) {
@@ -586,6 +624,13 @@ func (q *Queries) GetMonstersByTrait(ctx context.Context, trait pgtype.Text) ([]
 			&i.HpValue,
 			&i.PerceptionMod,
 			&i.PerceptionDetail,
+			&i.FoundryID,
+			&i.PackPath,
+			&i.ContentHash,
+			&i.LastSeenAt,
+			&i.RetiredAt,
+			&i.DataVersion,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -600,9 +645,9 @@ func (q *Queries) GetMonstersByTrait(ctx context.Context, trait pgtype.Text) ([]
 const searchMonsterByName = `-- name: SearchMonsterByName :many
 SELECT row_to_json(monster_data)
 FROM (
-  SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail
+  SELECT m.id, m.name, m.level, m.focus_points, m.traits_rarity, m.traits_size, m.attr_str, m.attr_dex, m.attr_con, m.attr_wis, m.attr_int, m.attr_cha, m.saves_fort, m.saves_fort_detail, m.saves_ref, m.saves_ref_detail, m.saves_will, m.saves_will_detail, m.saves_exception, m.ac_value, m.ac_detail, m.hp_detail, m.hp_value, m.perception_mod, m.perception_detail, m.foundry_id, m.pack_path, m.content_hash, m.last_seen_at, m.retired_at, m.data_version, m.source
   FROM monsters m
-  WHERE m.name ILIKE '%' || $1 || '%'
+  WHERE m.name ILIKE '%' || $1 || '%' AND m.retired_at IS NULL
 ) monster_data
 `
 